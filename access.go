@@ -0,0 +1,136 @@
+package simplewire
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// AccessCheck is called by a proxy registered with RegisterProxy with the name of each method before the method is
+// called on the component the proxy wraps.
+type AccessCheck func(method string)
+
+// proxies holds the function registered to wrap a component in a proxy for each interface type.
+var proxies = struct {
+	mu   sync.RWMutex
+	wrap map[reflect.Type]func(component interface{}, check AccessCheck) interface{}
+}{wrap: map[reflect.Type]func(component interface{}, check AccessCheck) interface{}{}}
+
+// RegisterProxy registers wrap as the way to wrap a component of the interface I in a proxy, which calls check
+// before forwarding each method to the component.  Proxies are usually registered by the code the simplewire proxy
+// command generates, rather than by hand.
+func RegisterProxy[I any](wrap func(component I, check AccessCheck) I) {
+	t := reflect.TypeOf((*I)(nil)).Elem()
+	if t.Kind() != reflect.Interface {
+		panic("simplewire: proxies can only be registered for interfaces, not " + t.String())
+	}
+	proxies.mu.Lock()
+	defer proxies.mu.Unlock()
+	proxies.wrap[t] = func(component interface{}, check AccessCheck) interface{} {
+		return wrap(component.(I), check)
+	}
+}
+
+// AccessViolation is a call to a component which had not finished initializing, made while the injector was
+// starting.  Such a call works or fails depending on the order the components are initialized in.
+type AccessViolation struct {
+	// Consumer is the name of the component whose field holds the component which was called.
+	Consumer string `json:"consumer"`
+	// Component is the name of the component which was called.
+	Component string `json:"component"`
+	Method    string `json:"method"`
+	// CallPath holds the functions on the stack when the method was called, starting with the caller, up to the
+	// lifecycle method which was running.
+	CallPath []string `json:"callPath"`
+}
+
+// WithAccessChecks is a debug mode which finds components that call their dependencies before the dependencies have
+// been initialized.  Each interface field is injected with a proxy of the component, when one has been registered
+// for the interface with RegisterProxy, which records the violation when it is called too early.  The violations are
+// reported by Injector.AccessViolations.  Since the fields no longer hold the components themselves, and every call
+// is checked, the mode is meant for debugging rather than production.
+func WithAccessChecks() Option {
+	return func(o *options) {
+		o.accessChecks = true
+	}
+}
+
+// AccessViolations reports each call to a component which had not finished initializing, in the order they were
+// made.  A method called more than once from the same consumer is only reported once.
+func (i *injector) AccessViolations() []AccessViolation {
+	i.violations.mu.Lock()
+	defer i.violations.mu.Unlock()
+	return append([]AccessViolation{}, i.violations.list...)
+}
+
+// violationLog holds the access violations of an injector.  Proxies may be called from any goroutine.
+type violationLog struct {
+	mu   sync.Mutex
+	list []AccessViolation
+}
+
+// proxy wraps the component named dependency, which is being injected into a field of the interface type t of the
+// component named consumer, in a registered proxy.  The value is returned unchanged if there is no proxy for t or
+// the dependency is not a component of this injector.
+func (i *injector) proxy(consumer, dependency string, t reflect.Type, v reflect.Value) reflect.Value {
+	proxies.mu.RLock()
+	wrap, ok := proxies.wrap[t]
+	proxies.mu.RUnlock()
+	if !ok {
+		return v
+	}
+	var dep *component
+	for _, c := range i.components {
+		if c.name == dependency {
+			dep = c
+			break
+		}
+	}
+	if dep == nil {
+		return v
+	}
+	return reflect.ValueOf(wrap(v.Interface(), func(method string) {
+		if dep.initialized || i.phase >= PhaseInit {
+			return
+		}
+		i.recordViolation(AccessViolation{
+			Consumer:  consumer,
+			Component: dep.name,
+			Method:    method,
+			CallPath:  callPath(),
+		})
+	}))
+}
+
+// recordViolation records a call to a component which had not finished initializing, unless it was recorded already.
+func (i *injector) recordViolation(v AccessViolation) {
+	i.violations.mu.Lock()
+	defer i.violations.mu.Unlock()
+	for _, recorded := range i.violations.list {
+		if recorded.Consumer == v.Consumer && recorded.Component == v.Component && recorded.Method == v.Method {
+			return
+		}
+	}
+	i.violations.list = append(i.violations.list, v)
+}
+
+// callPath returns the functions on the stack of the caller of a proxy method, up to the function which called the
+// lifecycle method.
+func callPath() []string {
+	// skip Callers, callPath, the check, and the proxy method
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	path := []string{}
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "github.com/jswidler/simplewire.initialize" {
+			break
+		}
+		path = append(path, frame.Function)
+		if !more {
+			break
+		}
+	}
+	return path
+}
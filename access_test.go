@@ -0,0 +1,94 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	RegisterProxy[Database](func(c Database, check AccessCheck) Database { return proxyDatabase{c: c, check: check} })
+}
+
+// proxyDatabase is a Database which checks each call, as the simplewire proxy command would generate.
+type proxyDatabase struct {
+	c     Database
+	check AccessCheck
+}
+
+func (p proxyDatabase) AccountByID(a0 string) (*Account, error) {
+	p.check("AccountByID")
+	return p.c.AccountByID(a0)
+}
+
+func (p proxyDatabase) AccountsByUserID(a0 string) ([]*Account, error) {
+	p.check("AccountsByUserID")
+	return p.c.AccountsByUserID(a0)
+}
+
+func (p proxyDatabase) UserByID(a0 string) (*User, error) {
+	p.check("UserByID")
+	return p.c.UserByID(a0)
+}
+
+func (p proxyDatabase) UserByUsername(a0 string) (*User, error) {
+	p.check("UserByUsername")
+	return p.c.UserByUsername(a0)
+}
+
+// LazyDB is a Database which must be initialized before it is used.
+type LazyDB struct {
+	MockDB
+}
+
+func (db *LazyDB) Init() error {
+	return nil
+}
+
+// Eager is a component which uses its Database during Init.
+type Eager struct {
+	DB Database `component:"db"`
+}
+
+func (e *Eager) Init() error {
+	_, _ = e.DB.UserByID("1")
+	return nil
+}
+
+// TestAccessChecks tests that a call to a component which has not been initialized is recorded with the call path,
+// and that calls in the right order are not.
+func TestAccessChecks(t *testing.T) {
+	eager := &Eager{}
+	injector, err := Connect("component", &struct {
+		Eager *Eager
+		DB    *LazyDB
+	}{eager, &LazyDB{}}, WithAccessChecks())
+	assert.NoError(t, err)
+	violations := injector.AccessViolations()
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "Eager", violations[0].Consumer)
+		assert.Equal(t, "DB", violations[0].Component)
+		assert.Equal(t, "UserByID", violations[0].Method)
+		assert.Equal(t, []string{"github.com/jswidler/simplewire.(*Eager).Init"}, violations[0].CallPath)
+	}
+	assert.IsType(t, proxyDatabase{}, eager.DB)
+
+	_, _ = eager.DB.UserByID("1")
+	assert.Len(t, injector.AccessViolations(), 1, "calls after startup should not be recorded")
+
+	injector, err = Connect("component", &struct {
+		DB    *LazyDB
+		Eager *Eager
+	}{&LazyDB{}, &Eager{}}, WithAccessChecks())
+	assert.NoError(t, err)
+	assert.Empty(t, injector.AccessViolations())
+
+	eager = &Eager{}
+	injector, err = Connect("component", &struct {
+		Eager *Eager
+		DB    *LazyDB
+	}{eager, &LazyDB{}})
+	assert.NoError(t, err)
+	assert.Empty(t, injector.AccessViolations())
+	assert.IsType(t, &LazyDB{}, eager.DB, "components should only be proxied with access checks")
+}
@@ -0,0 +1,217 @@
+package simplewire
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Bridge shares selected components of one injector with other, otherwise independent injectors, such as the
+// services of a modular monolith which each have their own reference but use the same database.  The injector which
+// exported the components owns them: only it initializes, starts, and stops them.  Injectors which import them with
+// WithBridge can inject them, but never call their lifecycle methods, so a shared component is only closed once.
+type Bridge struct {
+	from *injector
+	// names maps the lowercase name of each exported component to the name it was declared with
+	names map[string]string
+	// list holds the declared names in the order they were exported
+	list []string
+}
+
+// Export returns a Bridge which shares the named components of the injector with the injectors it is given to.  Only
+// components held by the injector itself can be exported, not those of its parent.
+func (i *injector) Export(names ...string) (*Bridge, error) {
+	b := &Bridge{from: i, names: map[string]string{}}
+	for _, name := range names {
+		refName, _, err := i.getOwnRefFieldByName(name)
+		if err == errFieldNotFound || (err == nil && i.allowed != nil && !i.allowed[strings.ToLower(name)]) {
+			return nil, errorf(CodeNotFound, "simplewire export failed - %s not found in reference struct", name)
+		} else if err == errFieldNotExported {
+			return nil, errorf(CodeNotExported, "simplewire export failed - %s must be exported from reference struct", name)
+		} else if err != nil {
+			return nil, errorf(ErrorCode(err), "simplewire export failed - %v", err)
+		}
+		if _, ok := b.names[strings.ToLower(refName)]; !ok {
+			b.names[strings.ToLower(refName)] = refName
+			b.list = append(b.list, refName)
+		}
+	}
+	return b, nil
+}
+
+// WithBridge makes the components exported by b available to the injector, as if they were in its reference.
+// Components of the injector's own reference take precedence over them, and they take precedence over the components
+// of a parent.  The injector which exported them must finish its Init phase before the Wire phase of this one.
+func WithBridge(b *Bridge) Option {
+	return func(o *options) {
+		o.bridges = append(append([]*Bridge{}, o.bridges...), b)
+	}
+}
+
+// getBridgedByName finds a component exported to the injector through a bridge.
+func (i *injector) getBridgedByName(name string) (string, interface{}, error) {
+	for _, b := range i.options.bridges {
+		if _, ok := b.names[strings.ToLower(name)]; ok {
+			return b.from.getOwnRefFieldByName(name)
+		}
+	}
+	return "", nil, errFieldNotFound
+}
+
+// checkBridges returns an error if an injector which exported components to this one has not been initialized, or
+// has been stopped.
+func (i *injector) checkBridges() error {
+	for _, b := range i.options.bridges {
+		if b.from.phase < PhaseInit || b.from.phase == PhaseStop {
+			return errorf(CodeWrongPhase, "simplewire wire failed - %s must be initialized by the injector which exported them first, but its last phase was %s", strings.Join(b.list, ", "), b.from.phase)
+		}
+	}
+	return nil
+}
+
+// Process runs several independent injectors in one process, such as one for each service of a modular monolith.
+// Injectors are brought up in the order they are added and stopped in the reverse order, so an injector which
+// imports components through a Bridge is always stopped before the injector which owns them.
+type Process struct {
+	names     []string
+	injectors []*injector
+}
+
+// NewProcess creates an empty Process.
+func NewProcess() *Process {
+	return &Process{}
+}
+
+// Add adds the injector under a name used in errors.  An injector which imports components through a Bridge must be
+// added after the injector which exported them.  A component which must be initialized, started, or stopped may not
+// be held by more than one injector of the process, since it would be stopped twice; it should be exported by one of
+// them instead.
+func (p *Process) Add(name string, added Injector) error {
+	i, ok := added.(*injector)
+	if !ok || i.allowed != nil {
+		return errorf(CodeNotPermitted, "simplewire process failed - %s must be an injector made by Register or Connect", name)
+	}
+	for x, existing := range p.injectors {
+		if p.names[x] == name {
+			return errorf(CodeDuplicate, "simplewire process failed - %s is added more than once", name)
+		} else if existing == i {
+			return errorf(CodeDuplicate, "simplewire process failed - %s was already added as %s", name, p.names[x])
+		}
+	}
+	for _, b := range i.options.bridges {
+		if p.nameOf(b.from) == "" {
+			return errorf(CodeInvalidWiring, "simplewire process failed - %s imports %s from an injector which must be added first", name, strings.Join(b.list, ", "))
+		}
+	}
+	for _, c := range i.components {
+		if !hasLifecycle(c.value) {
+			continue
+		}
+		for x, existing := range p.injectors {
+			for _, other := range existing.components {
+				if sameComponent(c.value, other.value) {
+					return errorf(CodeDuplicate, "simplewire process failed - %s of %s is also held by %s as %s; export it from one of them with a Bridge so it is only stopped once", c.name, name, p.names[x], other.name)
+				}
+			}
+		}
+	}
+	p.names = append(p.names, name)
+	p.injectors = append(p.injectors, i)
+	return nil
+}
+
+// Injector returns the injector added with the given name.
+func (p *Process) Injector(name string) (Injector, bool) {
+	for x, n := range p.names {
+		if n == name {
+			return p.injectors[x], true
+		}
+	}
+	return nil, false
+}
+
+// Start brings each injector through the Wire, Init, and Start phases it has not already run, one injector at a
+// time in the order they were added.  If an injector fails, the injectors which were already brought up are stopped
+// in the reverse order.
+func (p *Process) Start(ctx context.Context) error {
+	for x, i := range p.injectors {
+		err := startInjector(ctx, i)
+		if err != nil {
+			err = fmt.Errorf("simplewire process failed - could not start %s: %w", p.names[x], err)
+			if stopErr := stopInjectors(ctx, p.injectors[:x]); stopErr != nil {
+				return fmt.Errorf("%w - rollback failed: %v", err, stopErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every injector which was initialized in the reverse order they were added.  Every injector is stopped
+// even if some fail, in which case the first error is returned.
+func (p *Process) Stop(ctx context.Context) error {
+	return stopInjectors(ctx, p.injectors)
+}
+
+// nameOf returns the name the injector was added with, or an empty string if it was not added.
+func (p *Process) nameOf(i *injector) string {
+	for x, existing := range p.injectors {
+		if existing == i {
+			return p.names[x]
+		}
+	}
+	return ""
+}
+
+// startInjector runs the phases of i which have not run yet, up to the Start phase.
+func startInjector(ctx context.Context, i *injector) error {
+	if i.phase == PhaseRegister {
+		if err := i.wire(ctx); err != nil {
+			return err
+		}
+	}
+	if i.phase == PhaseWire {
+		if err := i.init(ctx); err != nil {
+			return err
+		}
+	}
+	if i.phase == PhaseInit {
+		return i.Start(ctx)
+	}
+	if i.phase == PhaseStop {
+		return errorf(CodeWrongPhase, "simplewire start failed - the injector was already stopped")
+	}
+	return nil
+}
+
+// stopInjectors stops the injectors which were initialized in the reverse order, returning the first error.
+func stopInjectors(ctx context.Context, injectors []*injector) error {
+	var firstErr error
+	for x := len(injectors) - 1; x >= 0; x-- {
+		i := injectors[x]
+		if i.phase != PhaseInit && i.phase != PhaseStart {
+			continue
+		}
+		if err := i.Stop(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// hasLifecycle reports whether v has any method which is called during the lifecycle of an injector.
+func hasLifecycle(v interface{}) bool {
+	switch v.(type) {
+	case Initializable, ContextInitializable, Starter, Stopper, shutdowner, io.Closer:
+		return true
+	}
+	return false
+}
+
+// sameComponent reports whether a and b are the same pointer.
+func sameComponent(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Ptr && vb.Kind() == reflect.Ptr && va.Type() == vb.Type() && va.Pointer() == vb.Pointer()
+}
@@ -0,0 +1,93 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// SharedDB is a database which counts how many times it is closed.
+type SharedDB struct {
+	MockDB
+	closed int
+}
+
+func (db *SharedDB) Close() error {
+	db.closed++
+	return nil
+}
+
+// Billing is a service which uses a database owned by another injector.
+type Billing struct {
+	DB Database `component:"db"`
+}
+
+// TestBridge tests that components exported by one injector can be injected by another, which does not stop them.
+func TestBridge(t *testing.T) {
+	ctx := context.Background()
+	log := []string{}
+	db := &SharedDB{}
+	core, err := Register("component", &struct {
+		DB     *SharedDB
+		Events *Recorder
+	}{DB: db, Events: &Recorder{Name: "core", Log: &log}})
+	assert.NoError(t, err)
+	bridge, err := core.Export("db")
+	assert.NoError(t, err)
+
+	billing := &Billing{}
+	billingInjector, err := Register("component", &struct {
+		Billing *Billing
+		Events  *Recorder
+	}{Billing: billing, Events: &Recorder{Name: "billing", Log: &log}}, WithBridge(bridge))
+	assert.NoError(t, err)
+	err = billingInjector.Wire()
+	assert.EqualError(t, err, "simplewire wire failed - DB must be initialized by the injector which exported them first, but its last phase was register")
+	assert.Equal(t, CodeWrongPhase, ErrorCode(err))
+
+	billingInjector, err = Register("component", &struct {
+		Billing *Billing
+		Events  *Recorder
+	}{Billing: billing, Events: &Recorder{Name: "billing", Log: &log}}, WithBridge(bridge))
+	assert.NoError(t, err)
+	process := NewProcess()
+	err = process.Add("billing", billingInjector)
+	assert.EqualError(t, err, "simplewire process failed - billing imports DB from an injector which must be added first")
+	assert.NoError(t, process.Add("core", core))
+	assert.NoError(t, process.Add("billing", billingInjector))
+	found, ok := process.Injector("billing")
+	assert.True(t, ok)
+	assert.Same(t, billingInjector, found)
+
+	assert.NoError(t, process.Start(ctx))
+	assert.Same(t, db, billing.DB, "billing should be injected with the DB of core")
+	assert.NoError(t, process.Stop(ctx))
+	assert.Equal(t, 1, db.closed, "the DB should only be closed by core")
+	assert.Equal(t, []string{
+		"init core", "start core",
+		"init billing", "start billing",
+		"stop billing", "stop core",
+	}, log)
+}
+
+// TestBridgeErrors tests that components can only be exported once they exist, and that a process does not let two
+// injectors own the same component.
+func TestBridgeErrors(t *testing.T) {
+	db := &SharedDB{}
+	first, err := Register("component", &struct{ DB *SharedDB }{db})
+	assert.NoError(t, err)
+	_, err = first.Export("missing")
+	assert.EqualError(t, err, "simplewire export failed - missing not found in reference struct")
+	assert.Equal(t, CodeNotFound, ErrorCode(err))
+
+	second, err := Register("component", &struct{ Database *SharedDB }{db})
+	assert.NoError(t, err)
+	process := NewProcess()
+	assert.NoError(t, process.Add("first", first))
+	err = process.Add("first", second)
+	assert.EqualError(t, err, "simplewire process failed - first is added more than once")
+	err = process.Add("second", second)
+	assert.EqualError(t, err, "simplewire process failed - Database of second is also held by first as DB; export it from one of them with a Bridge so it is only stopped once")
+	assert.Equal(t, CodeDuplicate, ErrorCode(err))
+}
@@ -0,0 +1,64 @@
+package simplewire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// budgetReportSize is how many of the slowest components are named in the message of a BudgetError.
+const budgetReportSize = 5
+
+// WithStartupBudget makes Connect fail when registering, wiring, and initializing the components takes longer than
+// budget, such as to stay within the deadline of a health check.  The components are stopped and a *BudgetError is
+// returned, which ranks the components by how long they took to initialize.  The budget does not include the Start
+// phase, which Connect does not run.
+func WithStartupBudget(budget time.Duration) Option {
+	return func(o *options) {
+		o.startupBudget = budget
+	}
+}
+
+// InitTiming is how long a single component took to initialize.
+type InitTiming struct {
+	Component string
+	Duration  time.Duration
+}
+
+// BudgetError is returned by Connect when startup takes longer than the budget given to WithStartupBudget.
+type BudgetError struct {
+	Budget  time.Duration
+	Elapsed time.Duration
+	// Slowest holds the time taken by each component which was initialized, slowest first.
+	Slowest []InitTiming
+}
+
+func (e *BudgetError) Error() string {
+	slowest := e.Slowest
+	if len(slowest) > budgetReportSize {
+		slowest = slowest[:budgetReportSize]
+	}
+	msgs := make([]string, len(slowest))
+	for x, timing := range slowest {
+		msgs[x] = fmt.Sprintf("%s %v", timing.Component, timing.Duration)
+	}
+	return fmt.Sprintf("simplewire connect failed - startup took %v, over the budget of %v; slowest components: %s", e.Elapsed, e.Budget, strings.Join(msgs, ", "))
+}
+
+// checkBudget returns a *BudgetError if elapsed is over the startup budget of the injector.
+func (i *injector) checkBudget(elapsed time.Duration) error {
+	if i.options.startupBudget <= 0 || elapsed <= i.options.startupBudget {
+		return nil
+	}
+	e := &BudgetError{Budget: i.options.startupBudget, Elapsed: elapsed, Slowest: []InitTiming{}}
+	for _, c := range i.components {
+		if c.initialized {
+			e.Slowest = append(e.Slowest, InitTiming{Component: c.name, Duration: c.initDuration})
+		}
+	}
+	sort.SliceStable(e.Slowest, func(a, b int) bool {
+		return e.Slowest[a].Duration > e.Slowest[b].Duration
+	})
+	return e
+}
@@ -0,0 +1,51 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Slow is a component which takes a while to initialize.
+type Slow struct {
+	Delay   time.Duration
+	stopped bool
+}
+
+func (s *Slow) Init() error {
+	time.Sleep(s.Delay)
+	return nil
+}
+
+func (s *Slow) Stop(ctx context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+// TestStartupBudget tests that Connect fails when startup takes longer than the budget, ranking the slowest components.
+func TestStartupBudget(t *testing.T) {
+	components := struct {
+		Quick  *Slow
+		Slower *Slow
+		Slow   *Slow
+	}{&Slow{}, &Slow{Delay: 30 * time.Millisecond}, &Slow{Delay: 10 * time.Millisecond}}
+
+	_, err := Connect("component", components, WithStartupBudget(time.Second))
+	assert.NoError(t, err)
+
+	_, err = Connect("component", components, WithStartupBudget(20*time.Millisecond))
+	var budgetErr *BudgetError
+	assert.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, 20*time.Millisecond, budgetErr.Budget)
+	assert.Greater(t, budgetErr.Elapsed, 40*time.Millisecond)
+	assert.Len(t, budgetErr.Slowest, 3)
+	assert.Equal(t, "Slower", budgetErr.Slowest[0].Component)
+	assert.Equal(t, "Slow", budgetErr.Slowest[1].Component)
+	assert.Equal(t, "Quick", budgetErr.Slowest[2].Component)
+	assert.True(t, components.Slow.stopped, "the components should be stopped")
+	assert.Contains(t, err.Error(), "simplewire connect failed - startup took ")
+	assert.Contains(t, err.Error(), "over the budget of 20ms; slowest components: Slower ")
+}
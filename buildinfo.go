@@ -0,0 +1,72 @@
+package simplewire
+
+import (
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// BuildInfoName is the name the component added by WithBuildInfo is injected with.
+const BuildInfoName = "buildinfo"
+
+// BuildInfo describes how the running binary was built, for logging and version endpoints.  It is added to the
+// injector by WithBuildInfo, and fields are empty when the information is not available.
+type BuildInfo struct {
+	// GoVersion is the version of Go the binary was built with.
+	GoVersion string `json:"goVersion"`
+	// Path is the import path of the main package.
+	Path string `json:"path"`
+	// Version is the version of the main module, which is "(devel)" when it was built from a working copy.
+	Version string `json:"version"`
+	// Revision is the version control revision the binary was built from.
+	Revision string `json:"revision,omitempty"`
+	// Time is the time of the revision.
+	Time time.Time `json:"time,omitempty"`
+	// Modified is true when the working copy had changes which were not committed.
+	Modified bool `json:"modified,omitempty"`
+}
+
+// readBuildInfo is replaced by tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// WithBuildInfo adds a *BuildInfo component named BuildInfoName, read from the binary with debug.ReadBuildInfo, so
+// any component can inject it.
+func WithBuildInfo() Option {
+	return func(o *options) {
+		o.buildInfo = true
+	}
+}
+
+// newBuildInfo reads the build info of the binary.
+func newBuildInfo() *BuildInfo {
+	b := &BuildInfo{}
+	info, ok := readBuildInfo()
+	if !ok {
+		return b
+	}
+	b.GoVersion = info.GoVersion
+	b.Path = info.Path
+	b.Version = info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.Time, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
+
+// registerBuildInfo adds the BuildInfo component after the other components.
+func (i *injector) registerBuildInfo() error {
+	if _, _, err := i.getOwnRefFieldByName(BuildInfoName); err != errFieldNotFound {
+		return errorf(CodeDuplicate, "simplewire connect failed - %s is provided more than once", BuildInfoName)
+	}
+	c := &component{name: BuildInfoName, value: newBuildInfo()}
+	i.provided[strings.ToLower(BuildInfoName)] = c
+	i.components = append(i.components, c)
+	return nil
+}
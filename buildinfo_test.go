@@ -0,0 +1,46 @@
+package simplewire
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Versioned is a component which reports the version of the binary.
+type Versioned struct {
+	BuildInfo *BuildInfo `component:"buildinfo"`
+}
+
+// TestBuildInfo tests that the build info of the binary can be injected.
+func TestBuildInfo(t *testing.T) {
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.21.0",
+			Path:      "example.com/app/cmd/server",
+			Main:      debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123abcd"},
+				{Key: "vcs.time", Value: "2024-05-06T07:08:09Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+	defer func() { readBuildInfo = debug.ReadBuildInfo }()
+
+	components := struct{ Versioned *Versioned }{&Versioned{}}
+	_, err := Connect("component", components, WithBuildInfo())
+	assert.NoError(t, err)
+	assert.Equal(t, &BuildInfo{
+		GoVersion: "go1.21.0",
+		Path:      "example.com/app/cmd/server",
+		Version:   "v1.2.3",
+		Revision:  "0123abcd",
+		Time:      time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		Modified:  true,
+	}, components.Versioned.BuildInfo)
+
+	_, err = Connect("component", struct{ BuildInfo *BuildInfo }{}, WithBuildInfo())
+	assert.EqualError(t, err, "simplewire connect failed - buildinfo is provided more than once")
+}
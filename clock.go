@@ -0,0 +1,76 @@
+package simplewire
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Clock tells the time and waits.  The injector uses its clock to time the lifecycle, for the events it writes, the
+// durations in its graph, the startup budget, and the drain timeout of consumers.  A fake clock can be given to
+// WithClock so that tests of timing and retries do not depend on how long the test machine takes, or have to really
+// sleep.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	// After returns a channel which receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock used unless another is given, which uses the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Random is a source of randomness, such as for the jitter added to retries, which tests can replace so that the
+// waits are the same on every run.  A *rand.Rand from math/rand is a Random.
+type Random interface {
+	// Float64 returns a number in [0.0, 1.0).
+	Float64() float64
+}
+
+// SystemRandom is the Random used unless another is given, which uses the shared source of math/rand.
+var SystemRandom Random = systemRandom{}
+
+type systemRandom struct{}
+
+func (systemRandom) Float64() float64 {
+	return rand.Float64()
+}
+
+// WithClock makes the injector use clock instead of the SystemClock.  The clock is also used by each TenantPool
+// whose parent is the injector.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// now returns the current time according to the injector's clock.
+func (i *injector) now() time.Time {
+	return i.options.clock.Now()
+}
+
+// since returns the time which has passed since t according to the injector's clock.
+func (i *injector) since(t time.Time) time.Duration {
+	return i.options.clock.Now().Sub(t)
+}
+
+// clockOf returns the clock of an injector, or the SystemClock if it is not an injector made by this package.
+func clockOf(inj Injector) Clock {
+	if i, ok := inj.(*injector); ok {
+		return i.options.clock
+	}
+	return SystemClock
+}
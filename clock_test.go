@@ -0,0 +1,82 @@
+package simplewire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is a Clock which only moves when it sleeps or is advanced.  The channels returned by After receive at
+// once, as if the time had already passed.
+type testClock struct {
+	now    time.Time
+	sleeps []time.Duration
+	afters []time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.afters = append(c.afters, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now.Add(d)
+	return ch
+}
+
+// Ticking is a component whose Init makes time pass on a clock.
+type Ticking struct {
+	Clock *testClock
+	Takes time.Duration
+}
+
+func (t *Ticking) Init() error {
+	t.Clock.now = t.Clock.now.Add(t.Takes)
+	return nil
+}
+
+// TestClock tests that the lifecycle is timed with the injector's clock.
+func TestClock(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	components := struct {
+		Fast *Ticking
+		Slow *Ticking
+	}{&Ticking{Clock: clock, Takes: time.Second}, &Ticking{Clock: clock, Takes: 3 * time.Second}}
+
+	injector, err := Connect("component", components, WithClock(clock))
+	assert.NoError(t, err)
+	graph := injector.Graph()
+	assert.Equal(t, time.Second, graph.Components[0].InitDuration)
+	assert.Equal(t, 3*time.Second, graph.Components[1].InitDuration)
+
+	_, err = Connect("component", components, WithClock(clock), WithStartupBudget(2*time.Second))
+	assert.EqualError(t, err, "simplewire connect failed - startup took 4s, over the budget of 2s; slowest components: Slow 3s, Fast 1s")
+
+	db, err := NewSQLDB("maindb", "simplewire-fake", "")
+	assert.NoError(t, err)
+	db.Clock = clock
+	testDriver.failures = 2
+	assert.NoError(t, db.Init())
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, clock.sleeps, "retries should wait on the clock")
+
+	clock.sleeps = nil
+	db.PingJitter = 0.5
+	db.Random = fixedRandom(0.5)
+	testDriver.failures = 1
+	assert.NoError(t, db.Init())
+	assert.Equal(t, []time.Duration{1250 * time.Millisecond}, clock.sleeps, "the jitter should come from the Random")
+}
+
+// fixedRandom is a Random which always returns the same number.
+type fixedRandom float64
+
+func (r fixedRandom) Float64() float64 {
+	return float64(r)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contract generates a test checking that the tagged fields of every component of a reference resolve.
+func contract(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("contract", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "the directory of the package declaring the reference")
+	out := flags.String("o", "simplewire_contract_test.go", "the file to write, relative to the package directory, or - for stdout")
+	tag := flags.String("tag", "inject", "the struct tag the components are wired with")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: simplewire contract [-dir DIR] [-o FILE] [-tag TAG] REFERENCE")
+		return 2
+	}
+	src, err := generateContract(*dir, filepath.Base(*out), *tag, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	if *out == "-" {
+		_, _ = stdout.Write(src)
+		return 0
+	}
+	err = os.WriteFile(filepath.Join(*dir, *out), src, 0o644)
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// contractRow is a component of the reference and the names of its fields with the tag.
+type contractRow struct {
+	component string
+	fields    []string
+}
+
+// generateContract returns the source of a test file which checks that the tagged fields of each component of the
+// named reference struct resolve, with stand-ins for the components.  Components are only checked when their type
+// is a pointer to a struct declared in the package in dir.  A stub is generated for each interface of the package
+// which a component of the reference is declared as, unless the package declares one already.  Interfaces of other
+// packages need a stub registered by hand.  The file named skip is not parsed, since it is the previous output.
+func generateContract(dir, skip, tag, reference string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkg, files, err := parsePackage(fset, dir, skip)
+	if err != nil {
+		return nil, err
+	}
+	structs := map[string]*ast.StructType{}
+	declared := map[string]bool{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				declared[ts.Name.Name] = true
+				if st, ok := ts.Type.(*ast.StructType); ok && ts.TypeParams == nil {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+	ref, ok := structs[reference]
+	if !ok {
+		return nil, fmt.Errorf("struct %s not found", reference)
+	}
+
+	rows := []contractRow{}
+	for _, field := range ref.Fields.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := star.X.(*ast.Ident)
+		if !ok || structs[ident.Name] == nil {
+			continue
+		}
+		tagged := taggedFields(structs[ident.Name], tag)
+		if len(tagged) == 0 {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.IsExported() {
+				rows = append(rows, contractRow{component: name.Name, fields: tagged})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no components of %s have fields with the %s tag", reference, tag)
+	}
+
+	// interfaces declared in the package are given a stub, unless the package has one already, so StandIns does not
+	// leave them nil
+	interfaces := declaredInterfaces(files)
+	stubbed := []string{}
+	missing := []string{}
+	for _, field := range ref.Fields.List {
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok || interfaces[ident.Name].iface == nil || declared["stub"+ident.Name] || contains(stubbed, ident.Name) {
+			continue
+		}
+		exported := false
+		for _, name := range field.Names {
+			exported = exported || name.IsExported()
+		}
+		if !exported {
+			continue
+		}
+		if _, err := interfaceMethods(interfaces, ident.Name, map[string]bool{}); err != nil {
+			missing = append(missing, fmt.Sprintf("%s (%v)", ident.Name, err))
+			continue
+		}
+		stubbed = append(stubbed, ident.Name)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no stand-ins can be generated for %s", strings.Join(missing, ", "))
+	}
+
+	var body bytes.Buffer
+	imports := map[string]string{
+		"testing":                        "",
+		"github.com/jswidler/simplewire": "",
+		"github.com/jswidler/simplewire/simplewiretest": "",
+	}
+	fmt.Fprintf(&body, "// TestWiringContract checks that the tagged fields of every component of %s resolve, with stand-ins for\n", reference)
+	body.WriteString("// the components themselves.\n")
+	body.WriteString("func TestWiringContract(t *testing.T) {\n")
+	fmt.Fprintf(&body, "\tinjector, err := simplewire.Register(%s, simplewiretest.StandIns(%s{}))\n", strconv.Quote(tag), reference)
+	body.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	body.WriteString("\ttests := []struct {\n\t\tcomponent string\n\t\tfields    []string\n\t}{\n")
+	for _, row := range rows {
+		quoted := make([]string, len(row.fields))
+		for x, f := range row.fields {
+			quoted[x] = strconv.Quote(f)
+		}
+		fmt.Fprintf(&body, "\t\t{%s, []string{%s}},\n", strconv.Quote(row.component), strings.Join(quoted, ", "))
+	}
+	body.WriteString("\t}\n")
+	body.WriteString("\tfor _, test := range tests {\n")
+	body.WriteString("\t\tt.Run(test.component, func(t *testing.T) {\n")
+	body.WriteString("\t\t\tsimplewiretest.AssertResolves(t, injector, test.component, test.fields...)\n")
+	body.WriteString("\t\t})\n\t}\n}\n")
+	if len(stubbed) > 0 {
+		body.WriteString("\n")
+		err = writeStubs(&body, fset, interfaces, stubbed, imports)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return generatedSource("contract", pkg, imports, body.Bytes())
+}
+
+// contains reports whether list holds s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// taggedFields returns the names of the fields of st which have the tag, sorted by name.
+func taggedFields(st *ast.StructType, tag string) []string {
+	names := []string{}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		value, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		if _, ok := reflect.StructTag(value).Lookup(tag); !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
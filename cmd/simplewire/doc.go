@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// dirList is a flag which may be given more than once.
+type dirList []string
+
+func (d *dirList) String() string {
+	return strings.Join(*d, ",")
+}
+
+func (d *dirList) Set(dir string) error {
+	*d = append(*d, dir)
+	return nil
+}
+
+// doc writes a markdown document describing the graph in a file.
+func doc(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("doc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var src dirList
+	flags.Var(&src, "src", "a directory to search, with its subdirectories, for the doc comments of the component types; may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: simplewire doc [-src DIR]... GRAPH")
+		return 2
+	}
+	g, err := readGraph(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	docs := map[string]string{}
+	for _, dir := range src {
+		err = typeDocs(dir, docs)
+		if err != nil {
+			fmt.Fprintf(stderr, "simplewire: %v\n", err)
+			return 1
+		}
+	}
+	err = g.WriteMarkdown(stdout, func(typeName string) string {
+		return docs[strings.TrimLeft(typeName, "*")]
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// typeDocs adds the doc comment of each type declared in the Go files under dir to docs, keyed by the package name
+// and type name the way reflect names types, such as "app.Users".  A type already in docs is not replaced.
+func typeDocs(dir string, docs map[string]string) error {
+	fset := token.NewFileSet()
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				comment := ts.Doc
+				if comment == nil && len(gen.Specs) == 1 {
+					comment = gen.Doc
+				}
+				key := f.Name.Name + "." + ts.Name.Name
+				if _, ok := docs[key]; !ok && comment != nil {
+					docs[key] = comment.Text()
+				}
+			}
+		}
+		return nil
+	})
+}
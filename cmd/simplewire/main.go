@@ -0,0 +1,118 @@
+// Command simplewire works with the graphs described by simplewire injectors.
+//
+// Usage:
+//
+//	simplewire diff [-json] OLD NEW
+//	simplewire stub [-dir DIR] [-o FILE] INTERFACE...
+//	simplewire doc [-src DIR]... GRAPH
+//	simplewire contract [-dir DIR] [-o FILE] [-tag TAG] REFERENCE
+//	simplewire proxy [-dir DIR] [-o FILE] INTERFACE...
+//
+// The diff subcommand compares two graphs, serialized as JSON by Injector.Graph or by the JSON format of
+// simplewire.DebugHandler, and reports the components and edges which were added, removed, or changed.
+//
+// The stub subcommand generates a stub of each named interface of the package in DIR, whose methods return zero
+// values or a configurable error.  The stubs are written to a test file of the package and registered with
+// simplewiretest, so simplewiretest.AutoStub can inject them into fields whose components are missing.
+//
+// The doc subcommand writes a markdown document describing each component of a serialized graph, with its type, its
+// dependencies, and its dependents.  The doc comments of the component types are found in the source under each
+// -src directory and included as descriptions.
+//
+// The contract subcommand generates a table-driven test for the package in DIR, which checks that the tagged fields
+// of every component of the named reference struct resolve.  The components are replaced with stand-ins from
+// simplewiretest.StandIns, so the test only depends on how the components are wired, not on how they are built.
+//
+// The proxy subcommand generates a proxy of each named interface of the package in DIR, which checks each call before
+// forwarding it to the component.  The proxies are registered with simplewire, so simplewire.WithAccessChecks can
+// inject them to find components which are called before they have been initialized.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jswidler/simplewire"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand named by args[0] and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: simplewire diff [-json] OLD NEW")
+		fmt.Fprintln(stderr, "       simplewire stub [-dir DIR] [-o FILE] INTERFACE...")
+		fmt.Fprintln(stderr, "       simplewire doc [-src DIR]... GRAPH")
+		fmt.Fprintln(stderr, "       simplewire contract [-dir DIR] [-o FILE] [-tag TAG] REFERENCE")
+		fmt.Fprintln(stderr, "       simplewire proxy [-dir DIR] [-o FILE] INTERFACE...")
+		return 2
+	}
+	switch args[0] {
+	case "diff":
+		return diff(args[1:], stdout, stderr)
+	case "stub":
+		return stub(args[1:], stdout, stderr)
+	case "doc":
+		return doc(args[1:], stdout, stderr)
+	case "contract":
+		return contract(args[1:], stdout, stderr)
+	case "proxy":
+		return proxy(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "simplewire: unknown subcommand %s\n", args[0])
+		return 2
+	}
+}
+
+// diff compares the graphs in two files.
+func diff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the difference as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: simplewire diff [-json] OLD NEW")
+		return 2
+	}
+	older, err := readGraph(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	newer, err := readGraph(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+
+	d := simplewire.DiffGraphs(older, newer)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(d)
+	} else {
+		fmt.Fprint(stdout, d.String())
+	}
+	return 0
+}
+
+// readGraph reads a graph serialized as JSON from the file at path.
+func readGraph(path string) (simplewire.Graph, error) {
+	var g simplewire.Graph
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return g, err
+	}
+	err = json.Unmarshal(b, &g)
+	if err != nil {
+		return g, fmt.Errorf("could not read graph from %s: %w", path, err)
+	}
+	return g, nil
+}
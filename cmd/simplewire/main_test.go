@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiff tests that the diff subcommand reports the difference between two graph files.
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	older := filepath.Join(dir, "old.json")
+	newer := filepath.Join(dir, "new.json")
+	assert.NoError(t, os.WriteFile(older, []byte(`{"components":[{"name":"users","type":"*app.Users"}],"edges":[]}`), 0o600))
+	assert.NoError(t, os.WriteFile(newer, []byte(`{"components":[{"name":"users","type":"*app.Users"},{"name":"db","type":"*sql.DB"}],"edges":[{"from":"users","field":"DB","to":"db"}]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"diff", older, newer}, &stdout, &stderr))
+	assert.Equal(t, "+ component db (*sql.DB)\n+ edge users.DB -> db\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, 2, run([]string{"diff", older}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"diff", older, filepath.Join(dir, "missing.json")}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"graph"}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
+
+// TestStub tests that the stub subcommand generates a stub of each interface.
+func TestStub(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mail.go"), []byte(`package mail
+
+import (
+	"context"
+	"io"
+)
+
+type Closer interface {
+	Close() error
+}
+
+type Mailer interface {
+	Closer
+	Send(ctx context.Context, to string, body []byte) (id string, err error)
+	Log() io.Writer
+	Ping()
+}
+
+type Reader interface {
+	io.Reader
+}
+`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"stub", "-dir", dir, "Mailer"}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	src, err := os.ReadFile(filepath.Join(dir, "simplewire_stubs_test.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by simplewire stub; DO NOT EDIT.
+
+package mail
+
+import (
+	"context"
+	"io"
+
+	"github.com/jswidler/simplewire/simplewiretest"
+)
+
+func init() {
+	simplewiretest.RegisterStub[Mailer](func(err error) Mailer { return stubMailer{err: err} })
+}
+
+// stubMailer is a Mailer whose methods return zero values and its error.
+type stubMailer struct {
+	err error
+}
+
+func (s stubMailer) Close() (r0 error) {
+	r0 = s.err
+	return
+}
+
+func (s stubMailer) Log() (r0 io.Writer) {
+	return
+}
+
+func (s stubMailer) Ping() {}
+
+func (s stubMailer) Send(context.Context, string, []byte) (r0 string, r1 error) {
+	r1 = s.err
+	return
+}
+`, string(src))
+
+	// generating again skips the previous output
+	assert.Equal(t, 0, run([]string{"stub", "-dir", dir, "Mailer"}, &stdout, &stderr))
+
+	assert.Equal(t, 1, run([]string{"stub", "-dir", dir, "Reader"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "interface Reader embeds a type from another package")
+	assert.Equal(t, 1, run([]string{"stub", "-dir", dir, "Missing"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "interface Missing not found")
+}
+
+// TestDoc tests that the doc subcommand describes the components of a graph with the doc comments of their types.
+func TestDoc(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "app"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app", "users.go"), []byte(`package app
+
+// Users manages the users of the application.
+type Users struct{}
+`), 0o600))
+	graph := filepath.Join(dir, "graph.json")
+	assert.NoError(t, os.WriteFile(graph, []byte(`{"components":[{"name":"Users","type":"*app.Users"}],"edges":[]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"doc", "-src", dir, graph}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "# Components\n\n## Users\n\nType: `*app.Users`\n\nUsers manages the users of the application.\n", stdout.String())
+}
+
+// TestContract tests that the contract subcommand generates a test of each component with tagged fields.
+func TestContract(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte(`package app
+
+type Components struct {
+	Users    *Users
+	Accounts *Accounts
+	Config   Config
+	DB       Database
+	internal *Users
+}
+
+type Users struct {
+	DB       Database `+"`inject:\"db\"`"+`
+	Accounts *Accounts `+"`inject:\"accounts\"`"+`
+	cache    map[string]string
+}
+
+type Accounts struct {
+	DB Database `+"`inject:\"db\"`"+`
+}
+
+type Config struct {
+	Name string
+}
+
+type Database interface {
+	Query(q string) error
+}
+`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"contract", "-dir", dir, "-o", "-", "Components"}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, `// Code generated by simplewire contract; DO NOT EDIT.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/jswidler/simplewire"
+	"github.com/jswidler/simplewire/simplewiretest"
+)
+
+// TestWiringContract checks that the tagged fields of every component of Components resolve, with stand-ins for
+// the components themselves.
+func TestWiringContract(t *testing.T) {
+	injector, err := simplewire.Register("inject", simplewiretest.StandIns(Components{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		component string
+		fields    []string
+	}{
+		{"Users", []string{"Accounts", "DB"}},
+		{"Accounts", []string{"DB"}},
+	}
+	for _, test := range tests {
+		t.Run(test.component, func(t *testing.T) {
+			simplewiretest.AssertResolves(t, injector, test.component, test.fields...)
+		})
+	}
+}
+
+func init() {
+	simplewiretest.RegisterStub[Database](func(err error) Database { return stubDatabase{err: err} })
+}
+
+// stubDatabase is a Database whose methods return zero values and its error.
+type stubDatabase struct {
+	err error
+}
+
+func (s stubDatabase) Query(string) (r0 error) {
+	r0 = s.err
+	return
+}
+`, stdout.String())
+
+	// an interface with a stub in the package is left to it
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "stubs_test.go"), []byte("package app\n\ntype stubDatabase struct{}\n"), 0o600))
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"contract", "-dir", dir, "-o", "-", "Components"}, &stdout, &stderr))
+	assert.NotContains(t, stdout.String(), "RegisterStub")
+
+	assert.Equal(t, 1, run([]string{"contract", "-dir", dir, "-tag", "wire", "Components"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no components of Components have fields with the wire tag")
+	assert.Equal(t, 1, run([]string{"contract", "-dir", dir, "Missing"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "struct Missing not found")
+	assert.Equal(t, 2, run([]string{"contract", "-dir", dir}, &stdout, &stderr))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "log.go"), []byte(`package app
+
+import "io"
+
+type Logger interface {
+	io.Writer
+}
+
+type Logged struct {
+	Users *Users
+	Log   Logger
+}
+`), 0o600))
+	assert.Equal(t, 1, run([]string{"contract", "-dir", dir, "Logged"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no stand-ins can be generated for Logger (interface Logger embeds a type from another package")
+}
+
+// TestProxy tests that the proxy subcommand generates a proxy of each interface which checks and forwards each call.
+func TestProxy(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mail.go"), []byte(`package mail
+
+import "context"
+
+type Mailer interface {
+	Send(ctx context.Context, to ...string) (id string, err error)
+	Count() int
+	Ping()
+}
+`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"proxy", "-dir", dir, "Mailer"}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	src, err := os.ReadFile(filepath.Join(dir, "simplewire_proxies.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by simplewire proxy; DO NOT EDIT.
+
+package mail
+
+import (
+	"context"
+
+	"github.com/jswidler/simplewire"
+)
+
+func init() {
+	simplewire.RegisterProxy[Mailer](func(c Mailer, check simplewire.AccessCheck) Mailer { return proxyMailer{c: c, check: check} })
+}
+
+// proxyMailer is a Mailer which checks each call with simplewire before making it.
+type proxyMailer struct {
+	c     Mailer
+	check simplewire.AccessCheck
+}
+
+func (p proxyMailer) Count() int {
+	p.check("Count")
+	return p.c.Count()
+}
+
+func (p proxyMailer) Ping() {
+	p.check("Ping")
+	p.c.Ping()
+}
+
+func (p proxyMailer) Send(a0 context.Context, a1 ...string) (string, error) {
+	p.check("Send")
+	return p.c.Send(a0, a1...)
+}
+`, string(src))
+
+	// generating again skips the previous output
+	assert.Equal(t, 0, run([]string{"proxy", "-dir", dir, "Mailer"}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"proxy", "-dir", dir, "Missing"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "interface Missing not found")
+	assert.Equal(t, 2, run([]string{"proxy", "-dir", dir}, &stdout, &stderr))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// proxy generates proxies of interfaces for simplewire.WithAccessChecks.
+func proxy(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("proxy", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "the directory of the package declaring the interfaces")
+	out := flags.String("o", "simplewire_proxies.go", "the file to write, relative to the package directory, or - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: simplewire proxy [-dir DIR] [-o FILE] INTERFACE...")
+		return 2
+	}
+	src, err := generateProxies(*dir, filepath.Base(*out), flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	if *out == "-" {
+		_, _ = stdout.Write(src)
+		return 0
+	}
+	err = os.WriteFile(filepath.Join(*dir, *out), src, 0o644)
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// generateProxies returns the source of a file which declares a proxy of each named interface of the package in dir,
+// and registers them with simplewire.  The file named skip is not parsed, since it is the previous output.
+func generateProxies(dir, skip string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkg, files, err := parsePackage(fset, dir, skip)
+	if err != nil {
+		return nil, err
+	}
+	interfaces := declaredInterfaces(files)
+
+	var body bytes.Buffer
+	imports := map[string]string{"github.com/jswidler/simplewire": ""}
+	body.WriteString("func init() {\n")
+	for _, name := range names {
+		fmt.Fprintf(&body, "\tsimplewire.RegisterProxy[%s](func(c %s, check simplewire.AccessCheck) %s { return proxy%s{c: c, check: check} })\n", name, name, name, name)
+	}
+	body.WriteString("}\n")
+	for _, name := range names {
+		methods, err := interfaceMethods(interfaces, name, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&body, "\n// proxy%s is a %s which checks each call with simplewire before making it.\n", name, name)
+		fmt.Fprintf(&body, "type proxy%s struct {\n\tc     %s\n\tcheck simplewire.AccessCheck\n}\n", name, name)
+		for _, m := range methods {
+			err := writeProxyMethod(&body, fset, name, m, imports)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return generatedSource("proxy", pkg, imports, body.Bytes())
+}
+
+// writeProxyMethod writes the method m of the proxy for the interface named iface, which checks the call and then
+// forwards it to the component.  The packages the method refers to are added to imports.
+func writeProxyMethod(w *bytes.Buffer, fset *token.FileSet, iface string, m stubMethod, imports map[string]string) error {
+	params := []string{}
+	args := []string{}
+	for _, field := range m.fn.Params.List {
+		typ, err := typeSource(fset, field.Type, m.file, imports)
+		if err != nil {
+			return err
+		}
+		_, variadic := field.Type.(*ast.Ellipsis)
+		for n := 0; n < nameCount(field); n++ {
+			a := fmt.Sprintf("a%d", len(params))
+			params = append(params, a+" "+typ)
+			if variadic {
+				a += "..."
+			}
+			args = append(args, a)
+		}
+	}
+	results := []string{}
+	if m.fn.Results != nil {
+		for _, field := range m.fn.Results.List {
+			typ, err := typeSource(fset, field.Type, m.file, imports)
+			if err != nil {
+				return err
+			}
+			for n := 0; n < nameCount(field); n++ {
+				results = append(results, typ)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nfunc (p proxy%s) %s(%s)", iface, m.name, strings.Join(params, ", "))
+	if len(results) == 1 {
+		fmt.Fprintf(w, " %s", results[0])
+	} else if len(results) > 1 {
+		fmt.Fprintf(w, " (%s)", strings.Join(results, ", "))
+	}
+	fmt.Fprintf(w, " {\n\tp.check(%q)\n\t", m.name)
+	if len(results) > 0 {
+		w.WriteString("return ")
+	}
+	fmt.Fprintf(w, "p.c.%s(%s)\n}\n", m.name, strings.Join(args, ", "))
+	return nil
+}
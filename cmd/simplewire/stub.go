@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stub generates stubs of interfaces for simplewiretest.AutoStub.
+func stub(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("stub", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "the directory of the package declaring the interfaces")
+	out := flags.String("o", "simplewire_stubs_test.go", "the file to write, relative to the package directory, or - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: simplewire stub [-dir DIR] [-o FILE] INTERFACE...")
+		return 2
+	}
+	src, err := generateStubs(*dir, filepath.Base(*out), flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	if *out == "-" {
+		_, _ = stdout.Write(src)
+		return 0
+	}
+	err = os.WriteFile(filepath.Join(*dir, *out), src, 0o644)
+	if err != nil {
+		fmt.Fprintf(stderr, "simplewire: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// declaredInterface is an interface type declared in the package, with the file it is declared in.
+type declaredInterface struct {
+	iface *ast.InterfaceType
+	file  *ast.File
+}
+
+// stubMethod is a method of an interface, with the file it is declared in for resolving its imports.
+type stubMethod struct {
+	name string
+	fn   *ast.FuncType
+	file *ast.File
+}
+
+// generateStubs returns the source of a file which declares a stub of each named interface of the package in dir,
+// and registers them with simplewiretest.  The file named skip is not parsed, since it is the previous output.
+func generateStubs(dir, skip string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkg, files, err := parsePackage(fset, dir, skip)
+	if err != nil {
+		return nil, err
+	}
+	interfaces := declaredInterfaces(files)
+
+	var body bytes.Buffer
+	imports := map[string]string{}
+	err = writeStubs(&body, fset, interfaces, names, imports)
+	if err != nil {
+		return nil, err
+	}
+	return generatedSource("stub", pkg, imports, body.Bytes())
+}
+
+// writeStubs writes a stub of each named interface and the init function which registers them with simplewiretest.
+// The packages the stubs refer to are added to imports.
+func writeStubs(w *bytes.Buffer, fset *token.FileSet, interfaces map[string]declaredInterface, names []string, imports map[string]string) error {
+	imports["github.com/jswidler/simplewire/simplewiretest"] = ""
+	w.WriteString("func init() {\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "\tsimplewiretest.RegisterStub[%s](func(err error) %s { return stub%s{err: err} })\n", name, name, name)
+	}
+	w.WriteString("}\n")
+	for _, name := range names {
+		methods, err := interfaceMethods(interfaces, name, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n// stub%s is a %s whose methods return zero values and its error.\n", name, name)
+		fmt.Fprintf(w, "type stub%s struct {\n\terr error\n}\n", name)
+		for _, m := range methods {
+			err := writeStubMethod(w, fset, name, m, imports)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parsePackage parses the Go files of the package in dir, except for its tests and the file named skip, which is the
+// previous output of a generator.  The name of the package is returned with the files.
+func parsePackage(fset *token.FileSet, dir, skip string) (string, []*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	pkg := ""
+	files := []*ast.File{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || e.Name() == skip {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		// generated files are written to a test file of the package itself, not to an external test package
+		if strings.HasSuffix(f.Name.Name, "_test") {
+			continue
+		}
+		pkg = f.Name.Name
+		files = append(files, f)
+	}
+	if pkg == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return pkg, files, nil
+}
+
+// generatedSource returns the formatted source of a file generated by the named subcommand, with the imports and
+// the declarations in body.
+func generatedSource(generator, pkg string, imports map[string]string, body []byte) ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by simplewire %s; DO NOT EDIT.\n\n", generator)
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	// standard library packages are listed first, as goimports would
+	sort.Slice(paths, func(a, b int) bool {
+		stdA, stdB := isStandard(paths[a]), isStandard(paths[b])
+		if stdA != stdB {
+			return stdA
+		}
+		return paths[a] < paths[b]
+	})
+	for x, p := range paths {
+		if x > 0 && isStandard(paths[x-1]) && !isStandard(p) {
+			src.WriteString("\n")
+		}
+		if imports[p] != "" {
+			fmt.Fprintf(&src, "\t%s %s\n", imports[p], strconv.Quote(p))
+		} else {
+			fmt.Fprintf(&src, "\t%s\n", strconv.Quote(p))
+		}
+	}
+	src.WriteString(")\n\n")
+	src.Write(body)
+	return format.Source(src.Bytes())
+}
+
+// declaredInterfaces returns the interface types declared in files, keyed by name.  Generic interfaces are skipped.
+func declaredInterfaces(files []*ast.File) map[string]declaredInterface {
+	interfaces := map[string]declaredInterface{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if iface, ok := ts.Type.(*ast.InterfaceType); ok && ts.TypeParams == nil {
+					interfaces[ts.Name.Name] = declaredInterface{iface: iface, file: f}
+				}
+			}
+		}
+	}
+	return interfaces
+}
+
+// interfaceMethods returns the methods of the named interface sorted by name, including the methods of the
+// interfaces it embeds from the same package.
+func interfaceMethods(interfaces map[string]declaredInterface, name string, seen map[string]bool) ([]stubMethod, error) {
+	decl, ok := interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found", name)
+	}
+	if seen[name] {
+		return nil, nil
+	}
+	seen[name] = true
+	methods := []stubMethod{}
+	for _, field := range decl.iface.Methods.List {
+		if fn, ok := field.Type.(*ast.FuncType); ok {
+			for _, n := range field.Names {
+				methods = append(methods, stubMethod{name: n.Name, fn: fn, file: decl.file})
+			}
+			continue
+		}
+		embedded, ok := field.Type.(*ast.Ident)
+		if ok && embedded.Name == "error" {
+			methods = append(methods, stubMethod{name: "Error", fn: &ast.FuncType{
+				Params:  &ast.FieldList{},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}},
+			}, file: decl.file})
+			continue
+		} else if !ok {
+			return nil, fmt.Errorf("interface %s embeds a type from another package, which is not supported; list its methods instead", name)
+		}
+		embeddedMethods, err := interfaceMethods(interfaces, embedded.Name, seen)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, embeddedMethods...)
+	}
+	sort.Slice(methods, func(a, b int) bool {
+		return methods[a].name < methods[b].name
+	})
+	return methods, nil
+}
+
+// nameCount returns the number of parameters or results declared by field, which is one when they are unnamed.
+func nameCount(field *ast.Field) int {
+	if len(field.Names) == 0 {
+		return 1
+	}
+	return len(field.Names)
+}
+
+// writeStubMethod writes the method m of the stub for the interface named iface.  Results of type error are set to
+// the stub's error, and the packages the method refers to are added to imports.
+func writeStubMethod(w *bytes.Buffer, fset *token.FileSet, iface string, m stubMethod, imports map[string]string) error {
+	params := []string{}
+	for _, field := range m.fn.Params.List {
+		typ, err := typeSource(fset, field.Type, m.file, imports)
+		if err != nil {
+			return err
+		}
+		for n := 0; n < nameCount(field); n++ {
+			params = append(params, typ)
+		}
+	}
+	results := []string{}
+	errResults := []string{}
+	if m.fn.Results != nil {
+		for _, field := range m.fn.Results.List {
+			typ, err := typeSource(fset, field.Type, m.file, imports)
+			if err != nil {
+				return err
+			}
+			for n := 0; n < nameCount(field); n++ {
+				r := fmt.Sprintf("r%d", len(results))
+				results = append(results, r+" "+typ)
+				if typ == "error" {
+					errResults = append(errResults, r)
+				}
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nfunc (s stub%s) %s(%s)", iface, m.name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(w, " (%s)", strings.Join(results, ", "))
+	}
+	if len(results) == 0 {
+		w.WriteString(" {}\n")
+		return nil
+	}
+	w.WriteString(" {\n")
+	for _, r := range errResults {
+		fmt.Fprintf(w, "\t%s = s.err\n", r)
+	}
+	w.WriteString("\treturn\n}\n")
+	return nil
+}
+
+// typeSource prints the type expression expr, adding the imports of file which it refers to.
+func typeSource(fset *token.FileSet, expr ast.Expr, file *ast.File, imports map[string]string) (string, error) {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		found := false
+		for _, spec := range file.Imports {
+			p, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(p)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if name == pkg.Name {
+				found = true
+				if spec.Name != nil {
+					imports[p] = spec.Name.Name
+				} else {
+					imports[p] = ""
+				}
+			}
+		}
+		if !found {
+			err = fmt.Errorf("could not find the import of %s", pkg.Name)
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = printer.Fprint(&b, fset, expr)
+	return b.String(), err
+}
+
+// isStandard reports whether the import path is of a standard library package, which have no dot in their first
+// element.
+func isStandard(importPath string) bool {
+	return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+}
@@ -0,0 +1,144 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Code identifies the kind of a failure, so that tools and tests can check for it without matching the text of the
+// error.  Codes are stable, and every error returned by simplewire has one, which is found with ErrorCode.
+type Code string
+
+const (
+	// CodeNotFound means a named component does not exist.
+	CodeNotFound Code = "not_found"
+	// CodeNotExported means a field of the reference which holds a component is not exported.
+	CodeNotExported Code = "not_exported"
+	// CodeAmbiguous means a name matches more than one field of the reference ignoring case.
+	CodeAmbiguous Code = "ambiguous"
+	// CodeNil means a component or a destination is nil.
+	CodeNil Code = "nil"
+	// CodePrivateField means a field to inject is not exported.
+	CodePrivateField Code = "private_field"
+	// CodeNotAssignable means a component cannot be assigned to the field it is injected into.
+	CodeNotAssignable Code = "not_assignable"
+	// CodeInvalidField means a field to inject cannot hold a component, such as a struct field without the value
+	// option.
+	CodeInvalidField Code = "invalid_field"
+	// CodeInvalidTag means a struct tag could not be parsed.
+	CodeInvalidTag Code = "invalid_tag"
+	// CodeInvalidWiring means the dependencies declared with a Wiring or by Injectable are not valid.
+	CodeInvalidWiring Code = "invalid_wiring"
+	// CodeInvalidLabel means a label could not be parsed.
+	CodeInvalidLabel Code = "invalid_label"
+	// CodeInvalidReference means the reference is not a struct, a pointer to a struct, or a Registry.
+	CodeInvalidReference Code = "invalid_reference"
+	// CodeInvalidDestination means a destination given to Inject is of a kind which cannot be injected.
+	CodeInvalidDestination Code = "invalid_destination"
+	// CodeDuplicate means a component is provided more than once.
+	CodeDuplicate Code = "duplicate"
+	// CodeConflict means a reference was connected again with a different tag.
+	CodeConflict Code = "conflict"
+	// CodeCycle means components depend on each other in a cycle which is not allowed.
+	CodeCycle Code = "cycle"
+	// CodeLifetime means a component was injected into a consumer whose lifetime does not allow it.
+	CodeLifetime Code = "lifetime"
+	// CodeBuildFailed means a Factory could not build an instance.
+	CodeBuildFailed Code = "build_failed"
+	// CodeInitFailed means a component returned an error from Init.
+	CodeInitFailed Code = "init_failed"
+	// CodeStartFailed means a component returned an error from Start.
+	CodeStartFailed Code = "start_failed"
+	// CodeStopFailed means a component returned an error when it was stopped, shut down, or closed.
+	CodeStopFailed Code = "stop_failed"
+	// CodePreloadFailed means a component returned an error from Preload, or could not be built by Preload.
+	CodePreloadFailed Code = "preload_failed"
+	// CodeNotImplemented means a component does not implement the interface an operation needs.
+	CodeNotImplemented Code = "not_implemented"
+	// CodeWrongPhase means an operation was attempted in the wrong phase of the lifecycle.
+	CodeWrongPhase Code = "wrong_phase"
+	// CodeNotPermitted means an operation is not permitted on a subset of an injector.
+	CodeNotPermitted Code = "not_permitted"
+	// CodeCanceled means the context given to ConnectContext was cancelled.
+	CodeCanceled Code = "canceled"
+	// CodeTimeout means the deadline of the context given to ConnectContext passed, or consumers did not finish
+	// draining before the Stop phase gave up on them.
+	CodeTimeout Code = "timeout"
+	// CodeBudgetExceeded means startup took longer than the budget given to WithStartupBudget.
+	CodeBudgetExceeded Code = "budget_exceeded"
+	// CodeLimitExceeded means the graph is larger than a limit given to WithLimits.
+	CodeLimitExceeded Code = "limit_exceeded"
+	// CodeDeprecated is not a failure, but is reported to the hook given to WithErrorHook each time a component which
+	// implements Deprecated is injected into a new field.
+	CodeDeprecated Code = "deprecated"
+	// CodeInternal means simplewire failed unexpectedly.
+	CodeInternal Code = "internal"
+)
+
+// ErrorCode returns the code of err, or an empty Code if err is nil or did not come from simplewire.
+func ErrorCode(err error) Code {
+	var coded interface{ ErrorCode() Code }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return ""
+}
+
+// codedError is an error with a Code.  Its message is the message of err.
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func (e *codedError) ErrorCode() Code {
+	return e.code
+}
+
+// errorf formats an error with the given code.
+func errorf(code Code, format string, args ...interface{}) error {
+	return &codedError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// withCode gives err the code, unless err is nil.
+func withCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+func (e *InitError) ErrorCode() Code {
+	return CodeInitFailed
+}
+
+func (e InitErrors) ErrorCode() Code {
+	return CodeInitFailed
+}
+
+func (e PreloadErrors) ErrorCode() Code {
+	return CodePreloadFailed
+}
+
+func (e *CanceledError) ErrorCode() Code {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	return CodeCanceled
+}
+
+func (e *BudgetError) ErrorCode() Code {
+	return CodeBudgetExceeded
+}
+
+func (e *WiringError) ErrorCode() Code {
+	return ErrorCode(e.Err)
+}
@@ -0,0 +1,96 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorCode tests that each kind of failure has a code.
+func TestErrorCode(t *testing.T) {
+	injector, err := Connect("component", struct {
+		DB     Database
+		Config Config
+		Nil    Database
+		hidden Database
+	}{DB: &MockDB{}})
+	assert.NoError(t, err)
+
+	var private struct {
+		db Database `component:"db"`
+	}
+	var notAssignable struct {
+		DB *Users `component:"db"`
+	}
+	var notPointer struct {
+		DB MockDB `component:"db"`
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		code Code
+	}{
+		{"not found", injector.Inject(&struct {
+			Missing Database `component:"missing"`
+		}{}), CodeNotFound},
+		{"not exported", injector.Inject(&struct {
+			Hidden Database `component:"hidden"`
+		}{}), CodeNotExported},
+		{"nil", injector.Inject(&struct {
+			Nil Database `component:"nil"`
+		}{}), CodeNil},
+		{"private", injector.Inject(&private), CodePrivateField},
+		{"not assignable", injector.Inject(&notAssignable), CodeNotAssignable},
+		{"invalid field", injector.Inject(&notPointer), CodeInvalidField},
+		{"invalid tag", injector.Inject(&struct {
+			DB Database `component:"db,sideways"`
+		}{}), CodeInvalidTag},
+		{"invalid destination", injector.Inject("string"), CodeInvalidDestination},
+		{"init failed", func() error {
+			_, err := Connect("component", struct{ F *Failing }{&Failing{Name: "f"}})
+			return err
+		}(), CodeInitFailed},
+		{"cycle", func() error {
+			_, err := Connect("component", struct {
+				Users    *Users
+				Accounts *AccountsS
+				DB       Database
+			}{&Users{}, &AccountsS{}, &MockDB{}}, WithCycleCheck())
+			return err
+		}(), CodeCycle},
+		{"timeout", func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+			defer cancel()
+			_, err := ConnectContext(ctx, "component", struct{ DB Database }{&MockDB{}})
+			return err
+		}(), CodeTimeout},
+		{"wrong phase", injector.Wire(), CodeWrongPhase},
+		{"invalid reference", func() error {
+			_, err := Connect("component", "string")
+			return err
+		}(), CodeInvalidReference},
+		{"start failed", func() error {
+			registered, _ := Connect("component", struct{ S *failingStart }{&failingStart{}})
+			return registered.Start(context.Background())
+		}(), CodeStartFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Error(t, test.err)
+			assert.Equal(t, test.code, ErrorCode(test.err), test.err.Error())
+		})
+	}
+	assert.Equal(t, Code(""), ErrorCode(nil))
+	assert.Equal(t, Code(""), ErrorCode(errors.New("other")))
+}
+
+// failingStart is a component whose Start always fails.
+type failingStart struct{}
+
+func (f *failingStart) Start(ctx context.Context) error {
+	return errors.New("start failed")
+}
@@ -0,0 +1,84 @@
+package simplewire
+
+import (
+	"reflect"
+	"sync"
+)
+
+// WithConnectOnce makes Connect return the injector which is already connected to the same reference pointer, rather
+// than initializing its components again, which helps when an application has several entry points.  Only injectors
+// connected with WithConnectOnce are shared, and the other options given to a later Connect are ignored, since the
+// injector already exists.  The reference is held until its injector is stopped, after which it is connected anew.
+func WithConnectOnce() Option {
+	return func(o *options) {
+		o.connectOnce = true
+	}
+}
+
+// connected holds the injectors created by Connect with WithConnectOnce for each reference pointer, so connecting the
+// same reference again does not initialize its components twice.
+var connected = struct {
+	mu      sync.Mutex
+	entries map[interface{}]*connection
+}{entries: map[interface{}]*connection{}}
+
+// connection is the result of connecting a reference.  done is closed once injector and err are set.
+type connection struct {
+	tag      string
+	done     chan struct{}
+	injector *injector
+	err      error
+}
+
+// connectKey returns the key the reference is cached under, which is the reference itself when it is a non-nil
+// pointer.  Other references are copied by Connect, so nil is returned and they are never cached.
+func connectKey(reference interface{}) interface{} {
+	v := reflect.ValueOf(reference)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	return reference
+}
+
+// connectOnce returns the injector which was connected for key, calling connect to create it if there is none.
+// Callers which arrive while the reference is being connected wait for it to finish.  A failed connection is not
+// kept, so connecting the reference again will retry.
+func connectOnce(key interface{}, tag string, connect func() (*injector, error)) (*injector, error) {
+	connected.mu.Lock()
+	if c, ok := connected.entries[key]; ok {
+		connected.mu.Unlock()
+		<-c.done
+		if c.tag != tag {
+			return nil, errorf(CodeConflict, "simplewire connect failed - reference was already connected with tag %s", c.tag)
+		}
+		return c.injector, c.err
+	}
+	c := &connection{tag: tag, done: make(chan struct{})}
+	connected.entries[key] = c
+	connected.mu.Unlock()
+
+	c.injector, c.err = connect()
+	if c.err == nil {
+		c.injector.connectedAs = key
+	}
+	connected.mu.Lock()
+	if c.err != nil {
+		delete(connected.entries, key)
+	}
+	connected.mu.Unlock()
+	close(c.done)
+	return c.injector, c.err
+}
+
+// forgetConnected removes the injector from the connected references, so the reference may be connected again.
+func forgetConnected(i *injector) {
+	if i.connectedAs == nil {
+		return
+	}
+	connected.mu.Lock()
+	if c, ok := connected.entries[i.connectedAs]; ok && c.injector == i {
+		delete(connected.entries, i.connectedAs)
+	}
+	connected.mu.Unlock()
+	i.connectedAs = nil
+}
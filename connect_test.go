@@ -0,0 +1,104 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConnectTwice tests that connecting the same reference pointer again with WithConnectOnce returns the existing
+// injector without initializing the components a second time.
+func TestConnectTwice(t *testing.T) {
+	log := []string{}
+	reference := &struct{ First *Recorder }{&Recorder{Name: "first", Log: &log}}
+	injector, err := Connect("component", reference, WithConnectOnce())
+	assert.NoError(t, err)
+	again, err := Connect("component", reference, WithConnectOnce())
+	assert.NoError(t, err)
+	assert.Same(t, injector, again, "the existing injector should be returned")
+	assert.Equal(t, []string{"init first"}, log)
+
+	_, err = Connect("service", reference, WithConnectOnce())
+	assert.EqualError(t, err, "simplewire connect failed - reference was already connected with tag component")
+
+	// once stopped, the reference can be connected again
+	assert.NoError(t, injector.Stop(context.Background()))
+	again, err = Connect("component", reference, WithConnectOnce())
+	assert.NoError(t, err)
+	assert.NotSame(t, injector, again)
+	assert.Equal(t, []string{"init first", "stop first", "init first"}, log)
+	assert.NoError(t, again.Stop(context.Background()))
+
+	// without the option, the reference is connected every time
+	log = []string{}
+	first, err := Connect("component", reference)
+	assert.NoError(t, err)
+	again, err = Connect("component", reference)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, again)
+	assert.Equal(t, []string{"init first", "init first"}, log)
+
+	// references which are not pointers are copied, so they are connected every time
+	log = []string{}
+	_, err = Connect("component", *reference, WithConnectOnce())
+	assert.NoError(t, err)
+	_, err = Connect("component", *reference, WithConnectOnce())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"init first", "init first"}, log)
+}
+
+// FailingStart is a component whose Start always fails.
+type FailingStart struct{}
+
+func (f *FailingStart) Start(ctx context.Context) error {
+	return errors.New("start failed")
+}
+
+// TestConnectOnceAfterRollback tests that a reference whose injector failed to start can be connected again.
+func TestConnectOnceAfterRollback(t *testing.T) {
+	reference := &struct{ Server *FailingStart }{&FailingStart{}}
+	injector, err := Connect("component", reference, WithConnectOnce())
+	assert.NoError(t, err)
+	assert.EqualError(t, injector.Start(context.Background()), "start failed")
+
+	again, err := Connect("component", reference, WithConnectOnce())
+	assert.NoError(t, err)
+	assert.NotSame(t, injector, again, "the stopped injector should not be returned")
+	assert.NoError(t, again.Stop(context.Background()))
+}
+
+// Blocking is a component whose initialization waits until its context is cancelled.
+type Blocking struct {
+	Started func()
+}
+
+func (b *Blocking) InitContext(ctx context.Context) error {
+	b.Started()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// TestConnectContext tests that cancelling the context stops the injector and reports how far it got.
+func TestConnectContext(t *testing.T) {
+	log := []string{}
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := ConnectContext(ctx, "component", struct {
+		First *Recorder
+		Slow  *Blocking
+		Third *Recorder
+	}{&Recorder{Name: "first", Log: &log}, &Blocking{Started: cancel}, &Recorder{Name: "third", Log: &log}})
+	assert.EqualError(t, err, "simplewire init canceled - context canceled after 1 of 3 components")
+	assert.ErrorIs(t, err, context.Canceled)
+	var canceled *CanceledError
+	if assert.ErrorAs(t, err, &canceled) {
+		assert.Equal(t, PhaseInit, canceled.Phase)
+		assert.Equal(t, []string{"First"}, canceled.Completed)
+		assert.Equal(t, []string{"Slow", "Third"}, canceled.Pending)
+	}
+	assert.Equal(t, []string{"init first", "stop first"}, log, "initialized components should be stopped")
+
+	_, err = ConnectContext(ctx, "component", struct{ First *Recorder }{&Recorder{Name: "first", Log: &log}})
+	assert.EqualError(t, err, "simplewire wire canceled - context canceled after 0 of 1 components")
+}
@@ -0,0 +1,147 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Consumer can be implemented by components which handle messages from outside the process, such as a queue
+// subscriber or an RPC listener.  Consumers are started after every other component, so nothing they call can still
+// be starting, and they are drained before any component is stopped, so nothing they call is stopped under them.
+type Consumer interface {
+	// Subscribe is called at the end of the Start phase, after every component has been started, in the order the
+	// consumers are initialized.  An error fails the Start phase.
+	Subscribe(ctx context.Context) error
+	// Consume is called in its own goroutine after Subscribe, and handles messages until ctx is cancelled at the
+	// beginning of the Stop phase, or before the consumer is stopped by Restart or StopGroup.  It should then finish
+	// the messages in flight and return.  Subscribe and Consume are called again when the consumer is started again.
+	Consume(ctx context.Context) error
+}
+
+// WithDrainTimeout limits how long the Stop phase waits for consumers to return from Consume after they are told to
+// stop, as measured by the injector's Clock.  Without it, the Stop phase waits until the context given to Stop is
+// done.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.drainTimeout = timeout
+	}
+}
+
+// consuming is a call to Consume which is running in its own goroutine.
+type consuming struct {
+	c *component
+	// cancel tells Consume to stop
+	cancel context.CancelFunc
+	// done is closed when Consume returns, after err is set
+	done chan struct{}
+	err  error
+}
+
+// startConsumers calls Subscribe on every component which implements Consumer, and then runs its Consume method in
+// a new goroutine.  The context given to Consume is only cancelled by drainConsumers.
+func (i *injector) startConsumers(ctx context.Context) error {
+	return i.subscribe(ctx, "start", i.components)
+}
+
+// subscribe calls Subscribe on each of the initialized components which implements Consumer and is not consuming
+// already, in order, and then runs its Consume method in a new goroutine.  The action names what failed in an error.
+func (i *injector) subscribe(ctx context.Context, action string, components []*component) error {
+	for _, c := range components {
+		consumer, ok := c.value.(Consumer)
+		if !ok || !c.initialized || i.isConsuming(c) {
+			continue
+		}
+		start := i.now()
+		err := consumer.Subscribe(ctx)
+		i.emitTimed("subscribe", PhaseStart, c.name, start, err)
+		if err != nil {
+			i.reportError(err, c.name, "")
+			return errorf(CodeStartFailed, "simplewire %s failed - could not subscribe %s: %w", action, c.name, err)
+		}
+		consumeCtx, cancel := context.WithCancel(context.Background())
+		run := &consuming{c: c, cancel: cancel, done: make(chan struct{})}
+		i.consuming = append(i.consuming, run)
+		go func() {
+			defer close(run.done)
+			start := i.now()
+			run.err = consumer.Consume(consumeCtx)
+			if run.err != nil && consumeCtx.Err() == nil {
+				// the consumer stopped on its own, rather than being told to
+				i.emitTimed("consume", PhaseStart, run.c.name, start, run.err)
+				i.reportError(run.err, run.c.name, "")
+			}
+		}()
+	}
+	return nil
+}
+
+// isConsuming reports whether the Consume method of c is running.
+func (i *injector) isConsuming(c *component) bool {
+	for _, run := range i.consuming {
+		if run.c == c {
+			return true
+		}
+	}
+	return false
+}
+
+// stopConsumers drains every running consumer, as drainConsumers does.
+func (i *injector) stopConsumers(ctx context.Context) error {
+	return i.drainConsumers(ctx, "stop", nil)
+}
+
+// drainConsumers cancels the context given to the running Consume methods of the components in only, or of every
+// component when only is nil, and waits for them to return, in the reverse order they were started, until ctx is
+// done or the drain timeout passes.  An error is returned if a consumer did not return in time, or returned an error
+// other than the cancellation.  The action names what failed in an error.
+func (i *injector) drainConsumers(ctx context.Context, action string, only map[*component]bool) error {
+	running := []*consuming{}
+	remaining := []*consuming{}
+	for _, run := range i.consuming {
+		if only == nil || only[run.c] {
+			run.cancel()
+			running = append(running, run)
+		} else {
+			remaining = append(remaining, run)
+		}
+	}
+	i.consuming = remaining
+	if len(running) == 0 {
+		return nil
+	}
+	var timeout <-chan time.Time
+	if i.options.drainTimeout > 0 {
+		timeout = i.options.clock.After(i.options.drainTimeout)
+	}
+
+	var firstErr, waitErr error
+	pending := []string{}
+	for x := len(running) - 1; x >= 0; x-- {
+		run := running[x]
+		start := i.now()
+		if waitErr == nil {
+			select {
+			case <-run.done:
+			case <-ctx.Done():
+				waitErr = ctx.Err()
+			case <-timeout:
+				waitErr = context.DeadlineExceeded
+			}
+		}
+		select {
+		case <-run.done:
+			i.emitTimed("drain", PhaseStop, run.c.name, start, run.err)
+			if run.err != nil && !errors.Is(run.err, context.Canceled) && firstErr == nil {
+				firstErr = errorf(CodeStopFailed, "simplewire %s failed - %s failed to consume: %w", action, run.c.name, run.err)
+			}
+		default:
+			pending = append(pending, run.c.name)
+		}
+	}
+	if len(pending) > 0 {
+		return errorf(CodeTimeout, "simplewire %s failed - %s did not finish draining: %v", action, strings.Join(pending, ", "), waitErr)
+	}
+	return firstErr
+}
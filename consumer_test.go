@@ -0,0 +1,157 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Queue is a consumer which records its lifecycle into the log of a Recorder.
+type Queue struct {
+	Recorder
+	// SubscribeErr is returned by Subscribe
+	SubscribeErr error
+	// ConsumeErr is returned by Consume without waiting to be stopped
+	ConsumeErr error
+	// Stuck makes Consume ignore its context until it is closed
+	Stuck chan struct{}
+}
+
+func (q *Queue) Subscribe(ctx context.Context) error {
+	*q.Log = append(*q.Log, "subscribe "+q.Name)
+	return q.SubscribeErr
+}
+
+func (q *Queue) Consume(ctx context.Context) error {
+	if q.ConsumeErr != nil {
+		return q.ConsumeErr
+	}
+	if q.Stuck != nil {
+		<-q.Stuck
+		return nil
+	}
+	<-ctx.Done()
+	*q.Log = append(*q.Log, "drain "+q.Name)
+	return ctx.Err()
+}
+
+// TestConsumer tests that consumers subscribe after every component has started, and are drained before any
+// component is stopped.
+func TestConsumer(t *testing.T) {
+	log := []string{}
+	components := struct {
+		Queue *Queue
+		DB    *Recorder
+	}{
+		Queue: &Queue{Recorder: Recorder{Name: "queue", Log: &log}},
+		DB:    &Recorder{Name: "db", Log: &log},
+	}
+	ctx := context.Background()
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Start(ctx))
+	assert.NoError(t, injector.Stop(ctx))
+	assert.Equal(t, []string{
+		"init queue", "init db",
+		"start queue", "start db",
+		"subscribe queue",
+		"drain queue",
+		"stop db", "stop queue",
+	}, log)
+}
+
+// TestConsumerErrors tests that a failure to subscribe rolls back the Start phase, and that consumers which fail or
+// do not drain in time are reported by the Stop phase.
+func TestConsumerErrors(t *testing.T) {
+	ctx := context.Background()
+	log := []string{}
+	injector, err := Connect("component", &struct {
+		DB    *Recorder
+		Queue *Queue
+	}{
+		DB:    &Recorder{Name: "db", Log: &log},
+		Queue: &Queue{Recorder: Recorder{Name: "queue", Log: &log}, SubscribeErr: errors.New("no broker")},
+	})
+	assert.NoError(t, err)
+	err = injector.Start(ctx)
+	assert.EqualError(t, err, "simplewire start failed - could not subscribe Queue: no broker")
+	assert.Equal(t, CodeStartFailed, ErrorCode(err))
+	assert.Equal(t, []string{"init db", "init queue", "start db", "start queue", "subscribe queue", "stop queue", "stop db"}, log)
+
+	failed := make(chan string, 1)
+	injector, err = Connect("component", &struct {
+		Queue *Queue
+	}{
+		Queue: &Queue{Recorder: Recorder{Name: "queue", Log: &[]string{}}, ConsumeErr: errors.New("connection lost")},
+	}, WithErrorHook(func(err error, component, field string) {
+		failed <- component
+	}))
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Start(ctx))
+	assert.Equal(t, "Queue", <-failed, "the failure should be reported when it happens")
+	assert.EqualError(t, injector.Stop(ctx), "simplewire stop failed - Queue failed to consume: connection lost")
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	clock := &testClock{}
+	log = []string{}
+	injector, err = Connect("component", &struct {
+		DB    *Recorder
+		Queue *Queue
+	}{
+		DB:    &Recorder{Name: "db", Log: &log},
+		Queue: &Queue{Recorder: Recorder{Name: "queue", Log: &log}, Stuck: stuck},
+	}, WithDrainTimeout(time.Minute), WithClock(clock))
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Start(ctx))
+	err = injector.Stop(ctx)
+	assert.EqualError(t, err, "simplewire stop failed - Queue did not finish draining: context deadline exceeded")
+	assert.Equal(t, []time.Duration{time.Minute}, clock.afters, "the drain timeout should be measured by the clock")
+	assert.Equal(t, CodeTimeout, ErrorCode(err))
+	assert.Equal(t, []string{"init db", "init queue", "start db", "start queue", "subscribe queue", "stop queue", "stop db"}, log,
+		"components should be stopped even if a consumer does not drain")
+}
+
+// TestConsumerRestart tests that a consumer is drained before it is stopped by Restart or StopGroup, and subscribes
+// again once it is started.
+func TestConsumerRestart(t *testing.T) {
+	type Subscriber struct {
+		Queue
+		DB *Recorder `component:"db"`
+	}
+	log := []string{}
+	components := struct {
+		DB    *Recorder
+		Queue *Subscriber `labels:"group=background"`
+	}{
+		DB:    &Recorder{Name: "db", Log: &log},
+		Queue: &Subscriber{Queue: Queue{Recorder: Recorder{Name: "queue", Log: &log}}},
+	}
+	ctx := context.Background()
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Start(ctx))
+
+	log = log[:0]
+	assert.NoError(t, injector.Restart(ctx, "db"))
+	assert.Equal(t, []string{
+		"drain queue",
+		"stop queue", "stop db",
+		"init db", "start db",
+		"init queue", "start queue",
+		"subscribe queue",
+	}, log)
+
+	log = log[:0]
+	assert.NoError(t, injector.StopGroup(ctx, "background"))
+	assert.Equal(t, []string{"drain queue", "stop queue"}, log)
+	assert.NoError(t, injector.StartGroup(ctx, "background"))
+	assert.Equal(t, []string{"drain queue", "stop queue", "start queue", "subscribe queue"}, log)
+
+	log = log[:0]
+	assert.NoError(t, injector.Stop(ctx))
+	assert.Equal(t, []string{"drain queue", "stop queue", "stop db"}, log, "the consumer should only be drained once")
+}
@@ -0,0 +1,85 @@
+package simplewire
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// DebugHandler returns an http.Handler which renders the components of the injector, the dependency graph, the
+// init timings, the health of each component, and the stats of the graph.  The response is HTML unless the request
+// asks for JSON, either with an Accept header or the query parameter format=json.  It is meant to be mounted like
+// expvar or pprof:
+//
+//	http.Handle("/debug/wire", simplewire.DebugHandler(injector))
+func DebugHandler(injector Injector) http.Handler {
+	return debugHandler{injector}
+}
+
+type debugHandler struct {
+	injector Injector
+}
+
+type debugReport struct {
+	Stats      Stats            `json:"stats"`
+	Components []debugComponent `json:"components"`
+	Edges      []Edge           `json:"edges"`
+}
+
+type debugComponent struct {
+	ComponentInfo
+	// Health is "ok" or the error returned by CheckHealth, and empty when the component is not a HealthChecker.
+	Health string `json:"health,omitempty"`
+}
+
+func (h debugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	graph := h.injector.Graph()
+	health := h.injector.Health(r.Context())
+	report := debugReport{Stats: graph.Stats(), Edges: graph.Edges}
+	for _, c := range graph.Components {
+		dc := debugComponent{ComponentInfo: c}
+		if err, ok := health[c.Name]; ok {
+			dc.Health = "ok"
+			if err != nil {
+				dc.Health = err.Error()
+			}
+		}
+		report.Components = append(report.Components, dc)
+	}
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = debugTemplate.Execute(w, report)
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>simplewire</title></head>
+<body>
+<h1>Stats</h1>
+<table>
+<tr><td>Components</td><td>{{.Stats.Components}}</td></tr>
+<tr><td>Edges</td><td>{{.Stats.Edges}}</td></tr>
+<tr><td>Max depth</td><td>{{.Stats.MaxDepth}}</td></tr>
+<tr><td>Init time</td><td>{{.Stats.InitDuration}}</td></tr>
+</table>
+<h1>Components</h1>
+<table>
+<tr><th>Name</th><th>Type</th><th>Init</th><th>Health</th></tr>
+{{range .Components}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.InitDuration}}</td><td>{{.Health}}</td></tr>
+{{end}}</table>
+<h1>Dependencies</h1>
+<table>
+<tr><th>Component</th><th>Field</th><th>Dependency</th></tr>
+{{range .Edges}}<tr><td>{{.From}}</td><td>{{.Field}}</td><td>{{.To}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,87 @@
+package simplewire
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (d MockDB) CheckHealth(ctx context.Context) error {
+	return nil
+}
+
+// TestGraph tests that the graph records every component and each dependency injected between them.
+func TestGraph(t *testing.T) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	graph := injector.Graph()
+	assert.Len(t, graph.Components, 3)
+	assert.Equal(t, "Users", graph.Components[0].Name)
+	assert.Equal(t, "*simplewire.Users", graph.Components[0].Type)
+	assert.Equal(t, []Edge{
+		{From: "Users", Field: "Accounts", To: "Accounts"},
+		{From: "Users", Field: "DB", To: "DB"},
+		{From: "Accounts", Field: "DB", To: "DB"},
+		{From: "Accounts", Field: "Users", To: "Users"},
+	}, graph.Edges)
+}
+
+// TestDebugHandler tests the JSON rendering of the debug handler.
+func TestDebugHandler(t *testing.T) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	DebugHandler(injector).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/wire?format=json", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	report := debugReport{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Len(t, report.Components, 3)
+	assert.Len(t, report.Edges, 4)
+	assert.Equal(t, "", report.Components[0].Health, "Users is not a HealthChecker")
+	assert.Equal(t, "ok", report.Components[2].Health, "DB should be healthy")
+
+	rec = httptest.NewRecorder()
+	DebugHandler(injector).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/wire", nil))
+	assert.Contains(t, rec.Body.String(), "<td>*simplewire.Users</td>")
+}
+
+// TestStats tests the aggregate statistics of a graph, including the depth of a chain that passes through a cycle.
+func TestStats(t *testing.T) {
+	graph := Graph{
+		Components: []ComponentInfo{
+			{Name: "handler", InitDuration: 2},
+			{Name: "users", InitDuration: 3},
+			{Name: "accounts"},
+			{Name: "db"},
+			{Name: "config"},
+		},
+		Edges: []Edge{
+			{From: "handler", Field: "Users", To: "users"},
+			{From: "users", Field: "Accounts", To: "accounts"},
+			{From: "accounts", Field: "Users", To: "users"},
+			{From: "accounts", Field: "DB", To: "db"},
+		},
+	}
+	assert.Equal(t, Stats{
+		Components:   5,
+		Edges:        4,
+		MaxDepth:     3,
+		InitDuration: 5,
+	}, graph.Stats())
+	assert.Equal(t, 0, Graph{}.Stats().MaxDepth)
+}
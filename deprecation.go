@@ -0,0 +1,52 @@
+package simplewire
+
+import (
+	"sync"
+)
+
+// Deprecated can be implemented by a component which should no longer be used.  Each time the component is injected,
+// the injector records where, so the remaining consumers can be found and migrated.
+type Deprecated interface {
+	// Deprecated returns a message for the consumers of the component, such as what to use instead.
+	Deprecated() string
+}
+
+// DeprecationUse is a field a deprecated component was injected into.
+type DeprecationUse struct {
+	// Component is the name of the deprecated component.
+	Component string `json:"component"`
+	// Consumer is the name of the component the field belongs to, or the type of the destination given to Inject.
+	Consumer string `json:"consumer"`
+	Field    string `json:"field"`
+	// Message is the message returned by the component's Deprecated method.
+	Message string `json:"message"`
+}
+
+// Deprecations reports each field a deprecated component has been injected into, in the order they were injected.
+// A field which is injected again, such as by Rewire, is only reported once.
+func (i *injector) Deprecations() []DeprecationUse {
+	i.deprecations.mu.Lock()
+	defer i.deprecations.mu.Unlock()
+	return append([]DeprecationUse{}, i.deprecations.list...)
+}
+
+// deprecationLog holds the deprecation uses of an injector.  Components may be injected from any goroutine.
+type deprecationLog struct {
+	mu   sync.Mutex
+	list []DeprecationUse
+}
+
+// recordDeprecation records that a deprecated component was injected, and reports it to the error hook the first
+// time, with the code CodeDeprecated.
+func (i *injector) recordDeprecation(use DeprecationUse) {
+	i.deprecations.mu.Lock()
+	for _, recorded := range i.deprecations.list {
+		if recorded == use {
+			i.deprecations.mu.Unlock()
+			return
+		}
+	}
+	i.deprecations.list = append(i.deprecations.list, use)
+	i.deprecations.mu.Unlock()
+	i.reportError(errorf(CodeDeprecated, "simplewire injected a deprecated component at %s:%s - %s: %s", use.Consumer, use.Field, use.Component, use.Message), use.Consumer, use.Field)
+}
@@ -0,0 +1,62 @@
+package simplewire
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// LegacyDB is a Database which has been deprecated.
+type LegacyDB struct {
+	MockDB
+}
+
+func (l *LegacyDB) Deprecated() string {
+	return "use db instead"
+}
+
+// TestDeprecations tests that each injection of a deprecated component is recorded and reported to the error hook.
+func TestDeprecations(t *testing.T) {
+	reported := []string{}
+	hook := func(err error, component, field string) {
+		reported = append(reported, fmt.Sprintf("%s %s.%s: %v", ErrorCode(err), component, field, err))
+	}
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+	}{&Users{}, &AccountsS{}, &LegacyDB{}}
+	injector, err := Connect("component", components, WithErrorHook(hook))
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Rewire())
+
+	assert.Equal(t, []DeprecationUse{
+		{Component: "DB", Consumer: "Users", Field: "DB", Message: "use db instead"},
+		{Component: "DB", Consumer: "Accounts", Field: "DB", Message: "use db instead"},
+	}, injector.Deprecations(), "rewiring should not record the same field twice")
+	assert.Equal(t, []string{
+		"deprecated Users.DB: simplewire injected a deprecated component at Users:DB - DB: use db instead",
+		"deprecated Accounts.DB: simplewire injected a deprecated component at Accounts:DB - DB: use db instead",
+	}, reported)
+}
+
+// TestConcurrentDeprecations tests that deprecated components can be injected from several goroutines at once.
+func TestConcurrentDeprecations(t *testing.T) {
+	injector, err := Connect("component", struct{ DB Database }{&LegacyDB{}})
+	assert.NoError(t, err)
+	type Repository struct {
+		DB Database `component:"db"`
+	}
+	var wg sync.WaitGroup
+	for x := 0; x < 8; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, injector.Inject(&Repository{}))
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, injector.Deprecations(), 1)
+}
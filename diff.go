@@ -0,0 +1,113 @@
+package simplewire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphDiff is the difference between two graphs, such as the graphs of two releases of an application.
+type GraphDiff struct {
+	AddedComponents   []ComponentInfo   `json:"addedComponents"`
+	RemovedComponents []ComponentInfo   `json:"removedComponents"`
+	ChangedComponents []ComponentChange `json:"changedComponents"`
+	AddedEdges        []Edge            `json:"addedEdges"`
+	RemovedEdges      []Edge            `json:"removedEdges"`
+}
+
+// ComponentChange is a component which is in both graphs with a different type.
+type ComponentChange struct {
+	Name    string `json:"name"`
+	OldType string `json:"oldType"`
+	NewType string `json:"newType"`
+}
+
+// DiffGraphs compares two graphs and reports the components and edges which were added or removed in newer, and
+// the components whose type changed.  Components are matched by name.  Each list is sorted, so the result does not
+// depend on the order the graphs were built in.
+func DiffGraphs(older, newer Graph) GraphDiff {
+	d := GraphDiff{
+		AddedComponents:   []ComponentInfo{},
+		RemovedComponents: []ComponentInfo{},
+		ChangedComponents: []ComponentChange{},
+		AddedEdges:        []Edge{},
+		RemovedEdges:      []Edge{},
+	}
+	oldComponents := map[string]ComponentInfo{}
+	for _, c := range older.Components {
+		oldComponents[c.Name] = c
+	}
+	newComponents := map[string]ComponentInfo{}
+	for _, c := range newer.Components {
+		newComponents[c.Name] = c
+		old, ok := oldComponents[c.Name]
+		if !ok {
+			d.AddedComponents = append(d.AddedComponents, c)
+		} else if old.Type != c.Type {
+			d.ChangedComponents = append(d.ChangedComponents, ComponentChange{Name: c.Name, OldType: old.Type, NewType: c.Type})
+		}
+	}
+	for _, c := range older.Components {
+		if _, ok := newComponents[c.Name]; !ok {
+			d.RemovedComponents = append(d.RemovedComponents, c)
+		}
+	}
+	d.AddedEdges = edgesNotIn(newer.Edges, older.Edges)
+	d.RemovedEdges = edgesNotIn(older.Edges, newer.Edges)
+
+	sort.Slice(d.AddedComponents, func(a, b int) bool { return d.AddedComponents[a].Name < d.AddedComponents[b].Name })
+	sort.Slice(d.RemovedComponents, func(a, b int) bool { return d.RemovedComponents[a].Name < d.RemovedComponents[b].Name })
+	sort.Slice(d.ChangedComponents, func(a, b int) bool { return d.ChangedComponents[a].Name < d.ChangedComponents[b].Name })
+	return d
+}
+
+// edgesNotIn returns the edges of a which are not in b, sorted.
+func edgesNotIn(a, b []Edge) []Edge {
+	inB := map[Edge]bool{}
+	for _, e := range b {
+		inB[e] = true
+	}
+	edges := []Edge{}
+	for _, e := range a {
+		if !inB[e] {
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(x, y int) bool {
+		if edges[x].From != edges[y].From {
+			return edges[x].From < edges[y].From
+		} else if edges[x].Field != edges[y].Field {
+			return edges[x].Field < edges[y].Field
+		}
+		return edges[x].To < edges[y].To
+	})
+	return edges
+}
+
+// Empty reports whether the graphs were the same.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedComponents) == 0 && len(d.RemovedComponents) == 0 && len(d.ChangedComponents) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// String summarizes the difference with one line per change.  Added lines start with +, removed lines with -, and
+// changed lines with ~.
+func (d GraphDiff) String() string {
+	var b strings.Builder
+	for _, c := range d.AddedComponents {
+		fmt.Fprintf(&b, "+ component %s (%s)\n", c.Name, c.Type)
+	}
+	for _, c := range d.RemovedComponents {
+		fmt.Fprintf(&b, "- component %s (%s)\n", c.Name, c.Type)
+	}
+	for _, c := range d.ChangedComponents {
+		fmt.Fprintf(&b, "~ component %s (%s -> %s)\n", c.Name, c.OldType, c.NewType)
+	}
+	for _, e := range d.AddedEdges {
+		fmt.Fprintf(&b, "+ edge %s.%s -> %s\n", e.From, e.Field, e.To)
+	}
+	for _, e := range d.RemovedEdges {
+		fmt.Fprintf(&b, "- edge %s.%s -> %s\n", e.From, e.Field, e.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffGraphs tests that the components and edges which changed between two graphs are reported.
+func TestDiffGraphs(t *testing.T) {
+	older := Graph{
+		Components: []ComponentInfo{
+			{Name: "users", Type: "*app.Users"},
+			{Name: "legacy", Type: "*app.Legacy"},
+			{Name: "db", Type: "*sql.DB"},
+		},
+		Edges: []Edge{
+			{From: "users", Field: "DB", To: "db"},
+			{From: "users", Field: "Legacy", To: "legacy"},
+		},
+	}
+	newer := Graph{
+		Components: []ComponentInfo{
+			{Name: "users", Type: "*app.Users"},
+			{Name: "db", Type: "*pgxpool.Pool"},
+			{Name: "payments", Type: "*app.Payments"},
+		},
+		Edges: []Edge{
+			{From: "users", Field: "DB", To: "db"},
+			{From: "users", Field: "Payments", To: "payments"},
+		},
+	}
+
+	d := DiffGraphs(older, newer)
+	assert.False(t, d.Empty())
+	assert.Equal(t, `+ component payments (*app.Payments)
+- component legacy (*app.Legacy)
+~ component db (*sql.DB -> *pgxpool.Pool)
++ edge users.Payments -> payments
+- edge users.Legacy -> legacy
+`, d.String())
+
+	assert.True(t, DiffGraphs(newer, newer).Empty())
+	assert.Equal(t, "", DiffGraphs(newer, newer).String())
+}
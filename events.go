@@ -0,0 +1,62 @@
+package simplewire
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is a single wiring or lifecycle action performed by an injector, as written by WithEventLog.
+type Event struct {
+	Time time.Time `json:"time"`
+	// Action is one of inject, init, start, stop, or phase.  A phase event is written when a phase finishes.
+	Action string `json:"action"`
+	// Phase is the lifecycle phase the action was part of, and is empty for calls to Inject.
+	Phase string `json:"phase,omitempty"`
+	// Component is the name of the component the action was performed on.  For calls to Inject, it is the name of
+	// the destination type.
+	Component string `json:"component,omitempty"`
+	// Field and Dependency are set for inject actions, naming the field which was set and the component it was set to.
+	Field      string `json:"field,omitempty"`
+	Dependency string `json:"dependency,omitempty"`
+	// Duration is how long the action took, for init, start, stop, and phase actions.
+	Duration time.Duration `json:"duration,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// WithEventLog writes an Event as a line of JSON to w for every wiring and lifecycle action, so startup behavior can
+// be ingested by log pipelines and compared between deployments.
+func WithEventLog(w io.Writer) Option {
+	return func(o *options) {
+		o.eventLog = &eventLog{enc: json.NewEncoder(w)}
+	}
+}
+
+type eventLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// emit writes the event if the injector has an event log.  Failures to write are ignored so that logging can never
+// interfere with wiring.
+func (i *injector) emit(e Event) {
+	if i.options.eventLog == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = i.now()
+	}
+	i.options.eventLog.mu.Lock()
+	defer i.options.eventLog.mu.Unlock()
+	_ = i.options.eventLog.enc.Encode(e)
+}
+
+// emitTimed writes an event for an action which started at start and finished with err.
+func (i *injector) emitTimed(action string, phase Phase, name string, start time.Time, err error) {
+	e := Event{Time: start, Action: action, Phase: phase.String(), Component: name, Duration: i.since(start)}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	i.emit(e)
+}
@@ -0,0 +1,39 @@
+package simplewire
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventLog tests that wiring and lifecycle actions are written as lines of JSON.
+func TestEventLog(t *testing.T) {
+	log := []string{}
+	components := struct {
+		DB       Database
+		Recorder *Disableable
+	}{
+		DB:       &MockDB{},
+		Recorder: &Disableable{Recorder: Recorder{Name: "recorder", Log: &log}, Enabled: true},
+	}
+	buf := &bytes.Buffer{}
+	_, err := Connect("component", components, WithEventLog(buf))
+	assert.NoError(t, err)
+
+	events := []Event{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		e := Event{}
+		assert.NoError(t, dec.Decode(&e))
+		assert.False(t, e.Time.IsZero())
+		events = append(events, Event{Action: e.Action, Phase: e.Phase, Component: e.Component, Field: e.Field, Dependency: e.Dependency})
+	}
+	assert.Equal(t, []Event{
+		{Action: "inject", Phase: "wire", Component: "Recorder", Field: "DB", Dependency: "DB"},
+		{Action: "phase", Phase: "wire"},
+		{Action: "init", Phase: "init", Component: "Recorder"},
+		{Action: "phase", Phase: "init"},
+	}, events)
+}
@@ -0,0 +1,20 @@
+package simplewire
+
+// ForEach calls fn with each component of the injector which implements the interface I, in the order they are
+// initialized, so bootstrap code can act on every component of a kind without keeping a list of them.  Components of
+// a parent injector are not included, and nil components are skipped.
+//
+//	simplewire.ForEach(injector, func(name string, p MigrationProvider) {
+//		migrations.Register(name, p.Migrations())
+//	})
+func ForEach[I any](inj Injector, fn func(name string, component I)) {
+	for _, c := range inj.Graph().Components {
+		v, ok := inj.Lookup(c.Name)
+		if !ok {
+			continue
+		}
+		if component, ok := v.(I); ok {
+			fn(c.Name, component)
+		}
+	}
+}
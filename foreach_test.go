@@ -0,0 +1,56 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MigrationProvider is implemented by components which have migrations to register.
+type MigrationProvider interface {
+	Migrations() []string
+}
+
+// Migrating is a component which provides migrations.
+type Migrating struct {
+	Name string
+	DB   Database `component:"db"`
+}
+
+func (m *Migrating) Migrations() []string {
+	return []string{m.Name}
+}
+
+// AuditModule is a module which provides a component with migrations.
+type AuditModule struct{}
+
+func (m AuditModule) Provide() map[string]interface{} {
+	return map[string]interface{}{
+		"audit": &Migrating{Name: "audit"},
+	}
+}
+
+// TestForEach tests that every component implementing an interface is visited in order, including components
+// provided by modules, and that other components are skipped.
+func TestForEach(t *testing.T) {
+	injector, err := Connect("component", &struct {
+		Users    *Migrating
+		DB       Database
+		Accounts *Migrating
+		Auditing AuditModule
+	}{
+		Users:    &Migrating{Name: "users"},
+		DB:       &MockDB{},
+		Accounts: &Migrating{Name: "accounts"},
+	})
+	assert.NoError(t, err)
+
+	names := []string{}
+	migrations := []string{}
+	ForEach(injector, func(name string, p MigrationProvider) {
+		names = append(names, name)
+		migrations = append(migrations, p.Migrations()...)
+	})
+	assert.Equal(t, []string{"Users", "Accounts", "audit"}, names)
+	assert.Equal(t, []string{"users", "accounts", "audit"}, migrations)
+}
@@ -0,0 +1,211 @@
+package simplewire
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Graph describes the components held by an injector and the dependencies injected between them.
+type Graph struct {
+	Components []ComponentInfo `json:"components"`
+	Edges      []Edge          `json:"edges"`
+}
+
+// ComponentInfo describes a single component of the graph.
+type ComponentInfo struct {
+	// Name is the name the component is injected with, as declared in the reference or by a module.
+	Name string `json:"name"`
+	// Type is the type of the component's value.
+	Type string `json:"type"`
+	// InitDuration is how long the Init method of the component took to run, or zero if it was not called.
+	InitDuration time.Duration `json:"initDuration"`
+	// Labels are the labels attached to the component.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Edge is a dependency that was injected into a field of one component from another component.
+type Edge struct {
+	From  string `json:"from"`
+	Field string `json:"field"`
+	To    string `json:"to"`
+	// Weak is true when the field's tag has the weak option, marking the dependency as intentionally cyclic.
+	Weak bool `json:"weak,omitempty"`
+}
+
+// HealthChecker can be implemented by a component to report whether it is healthy.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// Graph describes the components and the dependencies which were injected between them during the Wire phase.
+func (i *injector) Graph() Graph {
+	g := Graph{
+		Components: make([]ComponentInfo, 0, len(i.components)),
+		Edges:      append([]Edge{}, i.edges...),
+	}
+	for _, c := range i.components {
+		g.Components = append(g.Components, ComponentInfo{
+			Name:         c.name,
+			Type:         typeName(c.value),
+			InitDuration: c.initDuration,
+			Labels:       copyLabels(c.labels),
+		})
+	}
+	return g
+}
+
+// Health calls CheckHealth on every component that implements HealthChecker.  The result is keyed by component
+// name, and a nil error means the component is healthy.
+func (i *injector) Health(ctx context.Context) map[string]error {
+	health := map[string]error{}
+	for _, c := range i.components {
+		if checker, ok := c.value.(HealthChecker); ok {
+			health[c.name] = checker.CheckHealth(ctx)
+		}
+	}
+	return health
+}
+
+func typeName(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// Stats summarizes the size and shape of a graph.
+type Stats struct {
+	// Components is the number of components.
+	Components int `json:"components"`
+	// Edges is the number of dependencies injected between components.
+	Edges int `json:"edges"`
+	// MaxDepth is the number of components in the longest chain of dependencies.  Components which depend on each
+	// other in a cycle count as a single link of the chain.
+	MaxDepth int `json:"maxDepth"`
+	// InitDuration is the total time spent calling Init on the components.
+	InitDuration time.Duration `json:"initDuration"`
+}
+
+// Stats computes aggregate statistics of the graph.
+func (g Graph) Stats() Stats {
+	s := Stats{
+		Components: len(g.Components),
+		Edges:      len(g.Edges),
+	}
+	for _, c := range g.Components {
+		s.InitDuration += c.InitDuration
+	}
+
+	// collapse each cycle into a single node, then find the longest path through the nodes that remain
+	scc := g.stronglyConnected()
+	deps := make([]map[int]bool, len(scc.groups))
+	for x := range deps {
+		deps[x] = map[int]bool{}
+	}
+	for _, e := range g.Edges {
+		from, to := scc.group[e.From], scc.group[e.To]
+		if from != to {
+			deps[from][to] = true
+		}
+	}
+	// groups are numbered so that dependencies always come before their dependents
+	depth := make([]int, len(scc.groups))
+	for x := range scc.groups {
+		depth[x] = 1
+		for d := range deps[x] {
+			if depth[d]+1 > depth[x] {
+				depth[x] = depth[d] + 1
+			}
+		}
+		if depth[x] > s.MaxDepth {
+			s.MaxDepth = depth[x]
+		}
+	}
+	return s
+}
+
+// cycleGroups groups the components of a graph into strongly connected groups.
+type cycleGroups struct {
+	// groups holds the component names of each group, in reverse topological order
+	groups [][]string
+	// group maps each component name to the index of its group
+	group map[string]int
+}
+
+// stronglyConnected finds the strongly connected groups of the graph with Tarjan's algorithm.
+func (g Graph) stronglyConnected() cycleGroups {
+	adjacent := map[string][]string{}
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+	}
+	result := cycleGroups{group: map[string]int{}}
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		low[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, next := range adjacent[name] {
+			if _, seen := index[next]; !seen {
+				visit(next)
+				if low[next] < low[name] {
+					low[name] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[name] {
+				low[name] = index[next]
+			}
+		}
+		if low[name] == index[name] {
+			group := []string{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				result.group[top] = len(result.groups)
+				group = append(group, top)
+				if top == name {
+					break
+				}
+			}
+			result.groups = append(result.groups, group)
+		}
+	}
+	for _, c := range g.Components {
+		if _, seen := index[c.Name]; !seen {
+			visit(c.Name)
+		}
+	}
+	return result
+}
+
+// cycles returns the groups of components which depend on each other in a cycle, ignoring weak edges.  The names in
+// each group are sorted.
+func (g Graph) cycles() [][]string {
+	strong := Graph{Components: g.Components}
+	selfLoops := map[string]bool{}
+	for _, e := range g.Edges {
+		if e.Weak {
+			continue
+		}
+		strong.Edges = append(strong.Edges, e)
+		if e.From == e.To {
+			selfLoops[e.From] = true
+		}
+	}
+	cycles := [][]string{}
+	for _, group := range strong.stronglyConnected().groups {
+		if len(group) > 1 || selfLoops[group[0]] {
+			names := append([]string{}, group...)
+			sort.Strings(names)
+			cycles = append(cycles, names)
+		}
+	}
+	return cycles
+}
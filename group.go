@@ -0,0 +1,87 @@
+package simplewire
+
+import "context"
+
+// GroupLabel is the label which puts a component in a group for StartGroup and StopGroup, such as
+// `labels:"group=background"`.
+const GroupLabel = "group"
+
+// StopGroup pauses the components whose group label is group, calling Stop in the reverse order on each which
+// implements Stopper, while the rest of the graph keeps running.  Consumers in the group are drained first.
+// Components which depend on the group are not paused with it.  StopGroup must follow the Start phase, and
+// components which are already paused are skipped.
+func (i *injector) StopGroup(ctx context.Context, group string) error {
+	members, err := i.groupMembers("stop group", group)
+	if err != nil {
+		return err
+	}
+	draining := map[*component]bool{}
+	for _, c := range members {
+		draining[c] = true
+	}
+	if err := i.drainConsumers(ctx, "stop group", draining); err != nil {
+		return err
+	}
+	for x := len(members) - 1; x >= 0; x-- {
+		c := members[x]
+		if c.paused {
+			continue
+		}
+		if stopper, ok := c.value.(Stopper); ok {
+			start := i.now()
+			err := stopper.Stop(ctx)
+			i.emitTimed("stop", PhaseStop, c.name, start, err)
+			if err != nil {
+				i.reportError(err, c.name, "")
+				return errorf(CodeStopFailed, "simplewire stop group failed - could not stop %s: %w", c.name, err)
+			}
+		}
+		c.paused = true
+	}
+	return nil
+}
+
+// StartGroup resumes the components of a group paused by StopGroup, calling Start in order on each which implements
+// Starter, and then subscribing the consumers of the group again.  Components which are not paused are skipped.
+func (i *injector) StartGroup(ctx context.Context, group string) error {
+	members, err := i.groupMembers("start group", group)
+	if err != nil {
+		return err
+	}
+	for _, c := range members {
+		if !c.paused {
+			continue
+		}
+		if starter, ok := c.value.(Starter); ok {
+			start := i.now()
+			err := starter.Start(ctx)
+			i.emitTimed("start", PhaseStart, c.name, start, err)
+			if err != nil {
+				i.reportError(err, c.name, "")
+				return errorf(CodeStartFailed, "simplewire start group failed - could not start %s: %w", c.name, err)
+			}
+		}
+		c.paused = false
+	}
+	return i.subscribe(ctx, "start group", members)
+}
+
+// groupMembers returns the components in group, in the order they were started, checking that action may be taken.
+func (i *injector) groupMembers(action, group string) ([]*component, error) {
+	if err := i.checkNotSubset(action); err != nil {
+		return nil, err
+	}
+	if i.phase != PhaseStart {
+		return nil, errorf(CodeWrongPhase, "simplewire %s failed - must follow the start phase, but the last phase was %s", action, i.phase)
+	}
+	members := []*component{}
+	for _, c := range i.components {
+		if v, ok := c.labels[GroupLabel]; ok && v == group {
+			members = append(members, c)
+		}
+	}
+	if len(members) == 0 {
+		return nil, errorf(CodeNotFound, "simplewire %s failed - no components are in group %s", action, group)
+	}
+	return members, nil
+}
@@ -0,0 +1,39 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGroups tests that a group of components can be stopped and started while the rest keep running.
+func TestGroups(t *testing.T) {
+	log := []string{}
+	components := struct {
+		Server   *Recorder
+		Consumer *Recorder `labels:"group=background"`
+		Cleaner  *Recorder `labels:"group=background"`
+	}{&Recorder{Name: "server", Log: &log}, &Recorder{Name: "consumer", Log: &log}, &Recorder{Name: "cleaner", Log: &log}}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.EqualError(t, injector.StopGroup(ctx, "background"), "simplewire stop group failed - must follow the start phase, but the last phase was init")
+	assert.NoError(t, injector.Start(ctx))
+	log = log[:0]
+
+	assert.NoError(t, injector.StopGroup(ctx, "background"))
+	assert.NoError(t, injector.StopGroup(ctx, "background"), "stopping a paused group should do nothing")
+	assert.Equal(t, []string{"stop cleaner", "stop consumer"}, log)
+	assert.NoError(t, injector.StartGroup(ctx, "background"))
+	assert.Equal(t, []string{"stop cleaner", "stop consumer", "start consumer", "start cleaner"}, log)
+	assert.EqualError(t, injector.StopGroup(ctx, "missing"), "simplewire stop group failed - no components are in group missing")
+
+	log = log[:0]
+	assert.NoError(t, injector.StopGroup(ctx, "background"))
+	assert.NoError(t, injector.Stop(ctx))
+	assert.Equal(t, []string{"stop cleaner", "stop consumer", "stop server"}, log, "paused components should not be stopped twice")
+
+	err = injector.StartGroup(ctx, "missing")
+	assert.EqualError(t, err, "simplewire start group failed - must follow the start phase, but the last phase was stop")
+}
@@ -0,0 +1,117 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTPServer is a component which serves HTTP with a handler from the container.  It starts listening during the
+// Start phase and shuts down gracefully during the Stop phase.
+type HTTPServer struct {
+	// Handler serves every request.
+	Handler http.Handler
+	// Addr is the TCP address to listen on.  The default is ":http".
+	Addr string
+	// ReadTimeout, WriteTimeout, and IdleTimeout are passed on to the http.Server.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout limits how long the Stop phase waits for requests to finish.  It is unlimited when zero.
+	ShutdownTimeout time.Duration
+
+	names    HTTPServerNames
+	server   *http.Server
+	listener net.Listener
+	served   chan error
+}
+
+// HTTPServerNames holds the names of the components an HTTPServer is wired with.  Handler is injected as a
+// dependency and the rest are injected by value.  Any name which is empty is not injected, and the matching field of
+// the HTTPServer can be set directly instead.
+type HTTPServerNames struct {
+	Handler         string
+	Addr            string
+	ReadTimeout     string
+	WriteTimeout    string
+	IdleTimeout     string
+	ShutdownTimeout string
+}
+
+// NewHTTPServer creates a server which will be wired with the components in names.
+func NewHTTPServer(names HTTPServerNames) *HTTPServer {
+	return &HTTPServer{names: names}
+}
+
+// Dependencies implements Injectable, naming the components given to NewHTTPServer.
+func (s *HTTPServer) Dependencies() map[string]string {
+	tags := map[string]string{"Handler": s.names.Handler}
+	for field, name := range map[string]string{
+		"Addr":            s.names.Addr,
+		"ReadTimeout":     s.names.ReadTimeout,
+		"WriteTimeout":    s.names.WriteTimeout,
+		"IdleTimeout":     s.names.IdleTimeout,
+		"ShutdownTimeout": s.names.ShutdownTimeout,
+	} {
+		if name != "" {
+			tags[field] = name + ",value"
+		}
+	}
+	return tags
+}
+
+// ListenAddr returns the address the server is listening on once it has started, which is useful when Addr does
+// not name a port.
+func (s *HTTPServer) ListenAddr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Start listens on Addr and serves requests in the background.
+func (s *HTTPServer) Start(ctx context.Context) error {
+	if s.Handler == nil {
+		return errors.New("http server has no handler")
+	}
+	addr := s.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:      s.Handler,
+		ReadTimeout:  s.ReadTimeout,
+		WriteTimeout: s.WriteTimeout,
+		IdleTimeout:  s.IdleTimeout,
+	}
+	s.served = make(chan error, 1)
+	go func() {
+		s.served <- s.server.Serve(listener)
+	}()
+	return nil
+}
+
+// Stop gracefully shuts down the server, waiting for active requests to finish.
+func (s *HTTPServer) Stop(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	if s.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.ShutdownTimeout)
+		defer cancel()
+	}
+	err := s.server.Shutdown(ctx)
+	if served := <-s.served; served != http.ErrServerClosed && err == nil {
+		err = served
+	}
+	s.server = nil
+	return err
+}
@@ -0,0 +1,52 @@
+package simplewire
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHTTPServer tests that the server is wired with a handler and values from the container, serves requests once
+// started, and shuts down when stopped.
+func TestHTTPServer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})
+	components := struct {
+		Router        http.Handler
+		ListenAddr    string
+		ShutdownAfter time.Duration
+		Server        *HTTPServer
+	}{
+		Router:        mux,
+		ListenAddr:    "127.0.0.1:0",
+		ShutdownAfter: time.Second,
+		Server: NewHTTPServer(HTTPServerNames{
+			Handler:         "router",
+			Addr:            "listenaddr",
+			ShutdownTimeout: "shutdownafter",
+		}),
+	}
+	ctx := context.Background()
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:0", components.Server.Addr)
+	assert.Equal(t, time.Second, components.Server.ShutdownTimeout)
+	assert.NoError(t, injector.Start(ctx))
+
+	resp, err := http.Get("http://" + components.Server.ListenAddr().String())
+	assert.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "hello", string(body))
+
+	assert.NoError(t, injector.Stop(ctx))
+	_, err = http.Get("http://" + components.Server.ListenAddr().String())
+	assert.Error(t, err, "the server should no longer be listening")
+}
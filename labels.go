@@ -0,0 +1,205 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// labelTag is the key of the struct tag on a field of the reference which labels the component, such as
+// `labels:"tier=storage,team=payments"`.
+const labelTag = "labels"
+
+// WithLabels attaches labels to the named component, adding to any labels declared with a tag on the reference.
+// This is how components provided by a module are labeled.  Labels given for a name which is not a component cause
+// the Register phase to fail.
+func WithLabels(component string, labels map[string]string) Option {
+	return func(o *options) {
+		if o.labels == nil {
+			o.labels = map[string]map[string]string{}
+		}
+		name := strings.ToLower(component)
+		merged := map[string]string{}
+		for k, v := range o.labels[name] {
+			merged[k] = v
+		}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		o.labels[name] = merged
+	}
+}
+
+// parseLabels parses a tag of comma separated key=value pairs.
+func parseLabels(tag string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(tag, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("label %q must be of the form key=value", pair)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels, nil
+}
+
+// registerLabels sets the labels of each component from the tags of the reference and the WithLabels options.
+func (i *injector) registerLabels() error {
+	refType := i.reference.Type()
+	byName := map[string]*component{}
+	for _, c := range i.components {
+		byName[strings.ToLower(c.name)] = c
+		f, ok := refType.FieldByName(c.name)
+		if !ok {
+			continue
+		}
+		tag, ok := f.Tag.Lookup(labelTag)
+		if !ok {
+			continue
+		}
+		labels, err := parseLabels(tag)
+		if err != nil {
+			return errorf(CodeInvalidLabel, "simplewire register failed - %s has an invalid label tag: %v", c.name, err)
+		}
+		c.labels = labels
+	}
+	names := make([]string, 0, len(i.options.labels))
+	for name := range i.options.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		labels := i.options.labels[name]
+		c, ok := byName[name]
+		if !ok {
+			return errorf(CodeNotFound, "simplewire register failed - labels given for %s, which is not a component", name)
+		}
+		if c.labels == nil {
+			c.labels = map[string]string{}
+		}
+		for k, v := range labels {
+			c.labels[k] = v
+		}
+	}
+	return nil
+}
+
+// WithLabel returns the names of the components which have the label key set to value, in the order they are
+// initialized.
+func (i *injector) WithLabel(key, value string) []string {
+	names := []string{}
+	for _, c := range i.components {
+		if v, ok := c.labels[key]; ok && v == value {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// labeledNames returns the names of the components which have the label key set to value, starting with those of the
+// outermost parent, in the order each injector initializes them.  A name is only returned once, even when a child
+// declares a component of the same name as its parent.
+func (i *injector) labeledNames(key, value string) []string {
+	names := []string{}
+	if i.parent != nil {
+		names = i.parent.labeledNames(key, value)
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		seen[strings.ToLower(name)] = true
+	}
+	for _, name := range i.WithLabel(key, value) {
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// injectLabeled sets the slice field of a destination with the labeled option to every component which has the label
+// its tag names, such as `component:"tier=storage,labeled"`.  The components are injected as they would be into a
+// field of their own, in the order they are initialized, and each must be assignable to the elements of the slice.
+// The slice is empty when no component has the label.
+func (i *injector) injectLabeled(name, destStructName string, field injectField, dest reflect.Value, chain trace) error {
+	kv := strings.SplitN(field.ref, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return errorf(CodeInvalidTag, "simplewire inject failed at %s:%s - label %q must be of the form key=value", destStructName, field.name, field.ref)
+	} else if field.opts.value {
+		return errorf(CodeInvalidTag, "simplewire inject failed at %s:%s - the labeled and value options cannot be combined", destStructName, field.name)
+	} else if !unicode.IsUpper(rune(field.name[0])) {
+		return errorf(CodePrivateField, "simplewire inject failed at %s:%s - %s cannot be private", destStructName, field.name, field.name)
+	} else if dest.Kind() != reflect.Slice || (dest.Type().Elem().Kind() != reflect.Ptr && dest.Type().Elem().Kind() != reflect.Interface) {
+		return errorf(CodeInvalidField, "simplewire inject failed at %s:%s - %s must be a slice of pointers or interfaces to be injected by label", destStructName, field.name, field.name)
+	} else if !dest.CanSet() {
+		return errorf(CodeInvalidField, "simplewire inject failed at %s:%s - %s cannot be changed", destStructName, field.name, field.name)
+	}
+	consumer := name
+	if consumer == "" {
+		consumer = destStructName
+	}
+	elemType := dest.Type().Elem()
+	members := reflect.MakeSlice(dest.Type(), 0, 0)
+	for _, member := range i.labeledNames(kv[0], kv[1]) {
+		refName, ref, err := i.getRefFieldByName(member)
+		if err != nil {
+			// the component is hidden from this injector, such as by Subset
+			continue
+		}
+		deprecated, isDeprecated := ref.(Deprecated)
+		if f, ok := ref.(factory); ok {
+			ref, err = i.resolve(refName, f, chain.then(fmt.Sprintf("%s.%s (%s)", destStructName, field.name, refName)))
+			if _, ok := err.(*WiringError); ok {
+				return err
+			} else if err != nil {
+				return errorf(ErrorCode(err), "simplewire inject failed at %s:%s - %w", destStructName, field.name, err)
+			}
+		}
+		if perConsumer, ok := ref.(PerConsumer); ok {
+			ref = perConsumer.ProvideFor(consumer)
+		}
+		refValue := reflect.ValueOf(ref)
+		if !refValue.IsValid() || (refValue.Kind() == reflect.Ptr && refValue.IsNil()) {
+			return errorf(CodeNil, "simplewire inject failed at %s:%s - %s is nil in reference struct", destStructName, field.name, refName)
+		} else if !refValue.Type().AssignableTo(elemType) {
+			return errorf(CodeNotAssignable, "simplewire inject failed at %s:%s - %s is %s, which is not assignable to %s", destStructName, field.name, refName, refValue.Type(), elemType)
+		}
+		members = reflect.Append(members, refValue)
+
+		event := Event{Action: "inject", Component: name, Field: field.name, Dependency: refName}
+		if name != "" {
+			if err := i.checkEdgeLimit(); err != nil {
+				return errorf(ErrorCode(err), "simplewire inject failed at %s:%s - %v", destStructName, field.name, err)
+			}
+			i.edges = append(i.edges, Edge{From: name, Field: field.name, To: refName, Weak: field.opts.weak})
+			event.Phase = PhaseWire.String()
+		} else {
+			event.Component = destStructName
+		}
+		i.emit(event)
+		if isDeprecated {
+			i.recordDeprecation(DeprecationUse{
+				Component: refName,
+				Consumer:  event.Component,
+				Field:     field.name,
+				Message:   deprecated.Deprecated(),
+			})
+		}
+	}
+	dest.Set(members)
+	return nil
+}
+
+// copyLabels returns a copy of labels, or nil if there are none.
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLabels tests that components can be labeled with a tag on the reference or an option, and found by label.
+func TestLabels(t *testing.T) {
+	type LabeledComponents struct {
+		Users   *Users `labels:"tier=service"`
+		Modules AccountsModule
+	}
+	injector, err := Connect("component", LabeledComponents{Users: &Users{}},
+		WithLabels("Users", map[string]string{"team": "identity"}),
+		WithLabels("Accounts", map[string]string{"tier": "service", "team": "payments"}),
+		WithLabels("db", map[string]string{"tier": "storage"}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"Users", "Accounts"}, injector.WithLabel("tier", "service"))
+	assert.Equal(t, []string{"DB"}, injector.WithLabel("tier", "storage"))
+	assert.Equal(t, []string{"Users"}, injector.WithLabel("team", "identity"))
+	assert.Empty(t, injector.WithLabel("tier", "cache"))
+	assert.Equal(t, map[string]string{"tier": "service", "team": "identity"}, injector.Graph().Components[0].Labels)
+
+	_, err = Connect("component", LabeledComponents{}, WithLabels("missing", map[string]string{"tier": "cache"}))
+	assert.EqualError(t, err, "simplewire register failed - labels given for missing, which is not a component")
+
+	_, err = Connect("component", struct {
+		DB Database `labels:"storage"`
+	}{})
+	assert.EqualError(t, err, `simplewire register failed - DB has an invalid label tag: label "storage" must be of the form key=value`)
+}
+
+// Backups is a component which is injected with every component labeled as storage.
+type Backups struct {
+	Stores []*Recorder `component:"tier=storage,labeled"`
+}
+
+// TestLabeledInjection tests that a slice field with the labeled option is injected with every component which has
+// the label, in the order they are initialized, and that each is recorded as a dependency.
+func TestLabeledInjection(t *testing.T) {
+	log := []string{}
+	components := struct {
+		Backups *Backups
+		Primary *Recorder `labels:"tier=storage"`
+		Cache   *Recorder
+		Replica *Recorder `labels:"tier=storage"`
+	}{
+		Backups: &Backups{},
+		Primary: &Recorder{Name: "primary", Log: &log},
+		Cache:   &Recorder{Name: "cache", Log: &log},
+		Replica: &Recorder{Name: "replica", Log: &log},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Recorder{components.Primary, components.Replica}, components.Backups.Stores)
+	assert.Equal(t, []Edge{
+		{From: "Backups", Field: "Stores", To: "Primary"},
+		{From: "Backups", Field: "Stores", To: "Replica"},
+	}, injector.Graph().Edges)
+
+	caches := struct {
+		Caches []*Recorder `component:"tier=cache,labeled"`
+	}{}
+	assert.NoError(t, injector.Inject(&caches))
+	assert.NotNil(t, caches.Caches)
+	assert.Empty(t, caches.Caches)
+
+	t1 := struct {
+		Stores *Recorder `component:"tier=storage,labeled"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t1), "simplewire inject failed at anonymous struct:Stores - Stores must be a slice of pointers or interfaces to be injected by label")
+
+	t2 := struct {
+		Stores []*Recorder `component:"storage,labeled"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t2), `simplewire inject failed at anonymous struct:Stores - label "storage" must be of the form key=value`)
+
+	t3 := struct {
+		Stores []*Users `component:"tier=storage,labeled"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t3), "simplewire inject failed at anonymous struct:Stores - Primary is *simplewire.Recorder, which is not assignable to *simplewire.Users")
+}
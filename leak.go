@@ -0,0 +1,102 @@
+package simplewire
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// shutdowner is implemented by components with a Shutdown method, such as http.Server.
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// Leak is a component which was wired but never stopped or closed.
+type Leak struct {
+	Component string `json:"component"`
+	Type      string `json:"type"`
+}
+
+// WithLeakTracking remembers the injector from the end of the Wire phase until all of its components are stopped, so
+// that Leaks can report the components of every injector which was never stopped.
+func WithLeakTracking() Option {
+	return func(o *options) {
+		o.leakTracking = true
+	}
+}
+
+// tracked holds the injectors created with WithLeakTracking which have not been stopped, in the order they were wired.
+var tracked = struct {
+	mu        sync.Mutex
+	injectors []*injector
+}{}
+
+// track remembers the injector until it is untracked.
+func track(i *injector) {
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+	for _, t := range tracked.injectors {
+		if t == i {
+			return
+		}
+	}
+	tracked.injectors = append(tracked.injectors, i)
+}
+
+// untrack forgets the injector.
+func untrack(i *injector) {
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+	for x, t := range tracked.injectors {
+		if t == i {
+			tracked.injectors = append(tracked.injectors[:x], tracked.injectors[x+1:]...)
+			return
+		}
+	}
+}
+
+// Leaks reports the components of every injector created with WithLeakTracking which were wired but never stopped
+// or closed.  It can be called at the end of main or TestMain to find injectors which were never stopped.
+func Leaks() []Leak {
+	tracked.mu.Lock()
+	injectors := append([]*injector{}, tracked.injectors...)
+	tracked.mu.Unlock()
+	leaks := []Leak{}
+	for _, i := range injectors {
+		leaks = append(leaks, i.Leaks()...)
+	}
+	return leaks
+}
+
+// Leaks reports the components which implement Stopper or io.Closer or have a Shutdown method, and were wired but
+// have not been stopped or closed, in the order they were wired.  When a startup fails, the components it did not
+// reach are not stopped, since they were never initialized.  Those which are closed or shut down rather than stopped,
+// such as a *sql.DB, are still reported, since they may hold resources from when they were built.  Those skipped by
+// ConditionalInit are not.
+func (i *injector) Leaks() []Leak {
+	leaks := []Leak{}
+	for _, c := range i.components {
+		if c.open && needsStopping(c.value) {
+			leaks = append(leaks, Leak{Component: c.name, Type: typeName(c.value)})
+		}
+	}
+	return leaks
+}
+
+// needsStopping reports whether c would have a method called by stopComponent.
+func needsStopping(c interface{}) bool {
+	switch c.(type) {
+	case Stopper, shutdowner, io.Closer:
+		return true
+	}
+	return false
+}
+
+// holdsResources reports whether c may hold resources before it is initialized, which is assumed of components which
+// are closed or shut down rather than stopped.
+func holdsResources(c interface{}) bool {
+	if _, ok := c.(Stopper); ok {
+		return false
+	}
+	return needsStopping(c)
+}
@@ -0,0 +1,71 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Server is a component which is shut down rather than stopped.
+type Server struct {
+	shutdown bool
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdown = true
+	return nil
+}
+
+// TestLeaks tests that components which were wired and never stopped are reported.
+func TestLeaks(t *testing.T) {
+	log := []string{}
+	components := struct {
+		Server   *Server
+		Recorder *Recorder
+		DB       Database
+	}{&Server{}, &Recorder{Name: "recorder", Log: &log}, &MockDB{}}
+	injector, err := Connect("component", components, WithLeakTracking())
+	assert.NoError(t, err)
+
+	leaks := []Leak{
+		{Component: "Server", Type: "*simplewire.Server"},
+		{Component: "Recorder", Type: "*simplewire.Recorder"},
+	}
+	assert.Equal(t, leaks, injector.Leaks())
+	assert.Equal(t, leaks, Leaks())
+
+	assert.NoError(t, injector.Stop(context.Background()))
+	assert.True(t, components.Server.shutdown, "components with a Shutdown method should be shut down")
+	assert.Empty(t, injector.Leaks())
+	assert.Empty(t, Leaks(), "stopped injectors should no longer be tracked")
+}
+
+// TestLeaksAfterRollback tests that a failed startup only leaves the components which were wired but never
+// initialized, and may hold resources from when they were built, as leaks.
+func TestLeaksAfterRollback(t *testing.T) {
+	log := []string{}
+	components := struct {
+		First    *Server
+		Failed   *Failing
+		Recorder *Recorder
+		Disabled *OptionalConn
+	}{&Server{}, &Failing{Name: "failed"}, &Recorder{Name: "recorder", Log: &log}, &OptionalConn{}}
+	stopped, err := Connect("component", components, WithLeakTracking())
+	assert.Error(t, err)
+	assert.True(t, components.First.shutdown)
+	assert.Empty(t, stopped.Leaks(), "components which are stopped were never started, and disabled ones were skipped")
+	assert.Empty(t, Leaks(), "injectors which were rolled back should no longer be tracked")
+
+	conns := struct {
+		Failed *Failing
+		Conn   *OptionalConn
+	}{&Failing{Name: "failed"}, &OptionalConn{Enabled: true}}
+	rolledBack, err := Connect("component", conns, WithLeakTracking())
+	assert.Error(t, err)
+	assert.False(t, conns.Conn.closed, "the connection was never initialized")
+	leaks := []Leak{{Component: "Conn", Type: "*simplewire.OptionalConn"}}
+	assert.Equal(t, leaks, rolledBack.Leaks(), "a connection may be open before it is initialized")
+	assert.Equal(t, leaks, Leaks())
+	untrack(rolledBack.(*injector))
+}
@@ -0,0 +1,445 @@
+package simplewire
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Phase is a step in the lifecycle of an Injector.  Phases always run in the order they are declared.
+type Phase int
+
+const (
+	// PhaseRegister collects the components from the reference and any modules.
+	PhaseRegister Phase = iota
+	// PhaseWire injects the dependencies of every component.
+	PhaseWire
+	// PhaseInit calls Init on every component that implements Initializable.
+	PhaseInit
+	// PhaseStart calls Start on every component that implements Starter.
+	PhaseStart
+	// PhaseStop calls Stop on every component that implements Stopper, or Shutdown or Close if it has them.
+	PhaseStop
+)
+
+var phaseNames = []string{"register", "wire", "init", "start", "stop"}
+
+func (p Phase) String() string {
+	if p < 0 || int(p) >= len(phaseNames) {
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+	return phaseNames[p]
+}
+
+// Starter can be implemented to run logic once every component has been wired and initialized, such as starting
+// background work or listening on a port.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper can be implemented to release resources when the injector is stopped.  Components are stopped in the
+// reverse order they were started.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Wire runs the Wire phase, injecting the dependencies of every component.
+func (i *injector) Wire() error {
+	return i.wire(context.Background())
+}
+
+// wire runs the Wire phase, stopping if ctx is cancelled.
+func (i *injector) wire(ctx context.Context) error {
+	err := i.enterPhase(PhaseWire)
+	if err != nil {
+		return err
+	}
+	start := i.now()
+	if err := i.checkBridges(); err != nil {
+		return i.finishPhase(PhaseWire, start, err)
+	}
+	for x := 0; x < len(i.components); x++ {
+		c := i.components[x]
+		if err := ctx.Err(); err != nil {
+			return i.finishPhase(PhaseWire, start, i.canceled(PhaseWire, x, err))
+		}
+		if c.value == nil {
+			continue
+		}
+		i.wiring = c
+		err := i.injectSingle(c.name, c.value)
+		i.wiring = nil
+		if len(i.wiredScoped) > 0 {
+			// the Scoped instances built for the component were wired already, and come before it
+			i.components = append(i.components[:x], append(i.wiredScoped, i.components[x:]...)...)
+			x += len(i.wiredScoped)
+			i.wiredScoped = nil
+		}
+		if err != nil {
+			return i.finishPhase(PhaseWire, start, err)
+		}
+		c.open = true
+	}
+	if i.options.leakTracking {
+		track(i)
+	}
+	if i.options.cycleCheck {
+		if err := i.checkCycles(); err != nil {
+			return i.finishPhase(PhaseWire, start, err)
+		}
+	}
+	i.orderWeakCycles()
+	return i.finishPhase(PhaseWire, start, nil)
+}
+
+// orderWeakCycles reorders the components of each cycle which is broken by a weak edge, so that the components of
+// the cycle are initialized after the components they depend on without the weak option.  The dependency a weak edge
+// points to is only resolved after construction, so it may be initialized after the component which holds it.  The
+// components of such a cycle are reordered among the places they were declared in, and other components are not
+// moved.
+func (i *injector) orderWeakCycles() {
+	graph := i.Graph()
+	groups := graph.stronglyConnected()
+	weak := map[int]bool{}
+	for _, e := range graph.Edges {
+		if e.Weak && groups.group[e.From] == groups.group[e.To] {
+			weak[groups.group[e.From]] = true
+		}
+	}
+	for g := range weak {
+		depends := map[string][]string{}
+		for _, e := range graph.Edges {
+			if !e.Weak && e.From != e.To && groups.group[e.From] == g && groups.group[e.To] == g {
+				depends[e.From] = append(depends[e.From], e.To)
+			}
+		}
+		places := []int{}
+		remaining := []*component{}
+		for x, c := range i.components {
+			if group, ok := groups.group[c.name]; ok && group == g {
+				places = append(places, x)
+				remaining = append(remaining, c)
+			}
+		}
+		ordered := make([]*component, 0, len(remaining))
+		done := map[string]bool{}
+		for len(remaining) > 0 {
+			next := -1
+			for x, c := range remaining {
+				ready := true
+				for _, dep := range depends[c.name] {
+					ready = ready && done[dep]
+				}
+				if ready {
+					next = x
+					break
+				}
+			}
+			if next < 0 {
+				// the cycle is not broken by its weak edges, so its components are left as they were declared
+				ordered = nil
+				break
+			}
+			done[remaining[next].name] = true
+			ordered = append(ordered, remaining[next])
+			remaining = append(remaining[:next], remaining[next+1:]...)
+		}
+		for x, c := range ordered {
+			i.components[places[x]] = c
+		}
+	}
+}
+
+// checkCycles returns an error describing every cycle of dependencies which is not broken by a weak edge.
+func (i *injector) checkCycles() error {
+	cycles := i.Graph().cycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(cycles))
+	for x, names := range cycles {
+		msgs[x] = strings.Join(names, ", ")
+	}
+	return errorf(CodeCycle, "simplewire wire failed - dependency cycle between %s; add the weak option to a tag to allow it", strings.Join(msgs, "; between "))
+}
+
+// Init runs the Init phase, calling Init on every component that implements Initializable.  Components which
+// implement ConditionalInit are skipped when ShouldInit returns false, and are then not started or stopped either.
+// If any component fails to initialize, the components which were already initialized are stopped in the reverse
+// order.
+func (i *injector) Init() error {
+	return i.init(context.Background())
+}
+
+// init runs the Init phase, stopping if ctx is cancelled.
+func (i *injector) init(ctx context.Context) error {
+	err := i.enterPhase(PhaseInit)
+	if err != nil {
+		return err
+	}
+	phaseStart := i.now()
+	var errs InitErrors
+	for x, c := range i.components {
+		if err := ctx.Err(); err != nil {
+			return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), i.canceled(PhaseInit, x, err)))
+		}
+		if skipsInit(c.value) {
+			continue
+		}
+		if err := i.initTransients(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), i.canceled(PhaseInit, x, ctx.Err())))
+			} else if !i.options.continueOnInitError {
+				return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), err))
+			}
+			errs = append(errs, err)
+			continue
+		}
+		start := i.now()
+		called, err := initialize(ctx, c.value)
+		if called {
+			c.initDuration = i.since(start)
+			i.emitTimed("init", PhaseInit, c.name, start, err)
+		}
+		if err != nil && ctx.Err() != nil {
+			// the component gave up because ctx was cancelled
+			return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), i.canceled(PhaseInit, x, ctx.Err())))
+		}
+		if err != nil {
+			i.reportError(err, c.name, "")
+			initErr := &InitError{Component: c.name, Err: err}
+			if !i.options.continueOnInitError {
+				return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), initErr))
+			}
+			errs = append(errs, initErr)
+			continue
+		}
+		c.initialized = true
+	}
+	if len(errs) > 0 {
+		return i.finishPhase(PhaseInit, phaseStart, i.rollback(context.Background(), errs))
+	}
+	return i.finishPhase(PhaseInit, phaseStart, nil)
+}
+
+// initTransients initializes the Transient instances which were injected into c during the Wire phase, in the order
+// they were built, so that they are initialized after the components declared before c, like c itself.  The
+// instances belong to c, so they are not stopped by the injector.
+func (i *injector) initTransients(ctx context.Context, c *component) *InitError {
+	transients := c.transients
+	c.transients = nil
+	for _, t := range transients {
+		if skipsInit(t.value) {
+			continue
+		}
+		start := i.now()
+		called, err := initialize(ctx, t.value)
+		if called {
+			i.emitTimed("init", PhaseInit, t.name, start, err)
+		}
+		if err != nil {
+			i.reportError(err, t.name, "")
+			return &InitError{Component: t.name, Err: err}
+		}
+	}
+	return nil
+}
+
+// CanceledError is returned by ConnectContext when its context is cancelled, describing how far the injector got.
+type CanceledError struct {
+	// Phase is the phase which was running.
+	Phase Phase
+	// Completed holds the names of the components which finished the phase, in order.
+	Completed []string
+	// Pending holds the names of the components which had not finished the phase, in order.  The first one is the
+	// component which was being worked on.
+	Pending []string
+	// Err is the error from the context.
+	Err error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("simplewire %s canceled - %v after %d of %d components", e.Phase, e.Err, len(e.Completed), len(e.Completed)+len(e.Pending))
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// canceled returns the error for the phase p being cancelled by err before the component at index x finished.
+func (i *injector) canceled(p Phase, x int, err error) *CanceledError {
+	e := &CanceledError{Phase: p, Completed: []string{}, Pending: []string{}, Err: err}
+	for y, c := range i.components {
+		if y < x {
+			e.Completed = append(e.Completed, c.name)
+		} else {
+			e.Pending = append(e.Pending, c.name)
+		}
+	}
+	return e
+}
+
+// InitError is returned when a component fails to initialize.  Unlike errors from wiring, which are mistakes in how
+// the components are declared, an InitError may be caused by the environment and the initialization retried.
+type InitError struct {
+	// Component is the name of the component, or the type of the destination given to Inject.
+	Component string
+	Err       error
+}
+
+func (e *InitError) Error() string {
+	return fmt.Sprintf("simplewire init failed at %s - %v", e.Component, e.Err)
+}
+
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+// InitErrors is returned by the Init phase when the injector continues past failures, holding the error of each
+// component that failed to initialize in the order they were initialized.
+type InitErrors []*InitError
+
+func (e InitErrors) Error() string {
+	msgs := make([]string, len(e))
+	for x, err := range e {
+		msgs[x] = fmt.Sprintf("%s - %v", err.Component, err.Err)
+	}
+	return fmt.Sprintf("simplewire init failed for %d components: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Start runs the Start phase, calling Start on every component that implements Starter, except those which
+// ConditionalInit skipped.  If any component fails to start, every component is stopped in the reverse order.
+func (i *injector) Start(ctx context.Context) error {
+	err := i.enterPhase(PhaseStart)
+	if err != nil {
+		return err
+	}
+	phaseStart := i.now()
+	for _, c := range i.components {
+		if starter, ok := c.value.(Starter); ok && c.initialized {
+			start := i.now()
+			err := starter.Start(ctx)
+			i.emitTimed("start", PhaseStart, c.name, start, err)
+			if err != nil {
+				i.reportError(err, c.name, "")
+				return i.finishPhase(PhaseStart, phaseStart, i.rollback(ctx, withCode(CodeStartFailed, err)))
+			}
+		}
+	}
+	if err := i.startConsumers(ctx); err != nil {
+		return i.finishPhase(PhaseStart, phaseStart, i.rollback(ctx, err))
+	}
+	return i.finishPhase(PhaseStart, phaseStart, nil)
+}
+
+// Stop runs the Stop phase, calling Stop on every component that implements Stopper in the reverse order.
+// Components that do not implement Stopper but have a Shutdown method or implement io.Closer are shut down or closed
+// instead.  Every component is stopped even if some fail, in which case the first error is returned.  Stop may follow
+// either Init or Start.
+func (i *injector) Stop(ctx context.Context) error {
+	if err := i.checkNotSubset(PhaseStop.String()); err != nil {
+		return err
+	}
+	if i.phase != PhaseInit {
+		err := i.enterPhase(PhaseStop)
+		if err != nil {
+			return err
+		}
+	}
+	start := i.now()
+	err := i.stopComponents(ctx)
+	i.phase = PhaseStop
+	forgetConnected(i)
+	if len(i.Leaks()) == 0 {
+		untrack(i)
+	}
+	i.emitTimed("phase", PhaseStop, "", start, err)
+	return err
+}
+
+// rollback stops the components which were initialized after a failed startup.  The error which caused the failure
+// is returned, wrapped with the error from stopping the components if there was one.
+func (i *injector) rollback(ctx context.Context, cause error) error {
+	err := i.stopComponents(ctx)
+	i.phase = PhaseStop
+	forgetConnected(i)
+	if len(i.Leaks()) == 0 {
+		untrack(i)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - rollback failed: %v", cause, err)
+	}
+	return cause
+}
+
+// stopComponents drains the consumers, then stops every initialized component in the reverse order.  Components which
+// were wired but never initialized are not stopped, and are no longer open unless they hold resources, as described
+// by Leaks.  Every component is stopped even if some fail, in which case the first error is returned.
+func (i *injector) stopComponents(ctx context.Context) error {
+	firstErr := i.stopConsumers(ctx)
+	for x := len(i.components) - 1; x >= 0; x-- {
+		c := i.components[x]
+		if !c.initialized {
+			// the component was wired but never initialized, so there is nothing to stop, but it may still hold
+			// resources it acquired when it was built
+			c.open = !skipsInit(c.value) && holdsResources(c.value)
+			continue
+		}
+		c.initialized = false
+		c.open = false
+		paused := c.paused
+		c.paused = false
+		if _, ok := c.value.(Stopper); ok && paused {
+			// the component was already stopped with its group
+			continue
+		}
+		start := i.now()
+		called, err := stopComponent(ctx, c.value)
+		if called {
+			i.emitTimed("stop", PhaseStop, c.name, start, err)
+		}
+		if err != nil {
+			i.reportError(err, c.name, "")
+			if firstErr == nil {
+				firstErr = withCode(CodeStopFailed, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// enterPhase checks that the phase which completed last is the one that comes before p.
+func (i *injector) enterPhase(p Phase) error {
+	if err := i.checkNotSubset(p.String()); err != nil {
+		return err
+	}
+	if i.phase != p-1 {
+		return errorf(CodeWrongPhase, "simplewire %s failed - must follow the %s phase, but the last phase was %s", p, p-1, i.phase)
+	}
+	return nil
+}
+
+// finishPhase records that the phase p, which began at start, has finished with err.  The phase is only marked as
+// completed when err is nil.  The error is returned unchanged.
+func (i *injector) finishPhase(p Phase, start time.Time, err error) error {
+	if err == nil {
+		i.phase = p
+	}
+	i.emitTimed("phase", p, "", start, err)
+	return err
+}
+
+// stopComponent calls Stop if c implements Stopper, Shutdown if it has a Shutdown method like http.Server, or Close
+// if it implements io.Closer.  It reports whether any method was called.
+func stopComponent(ctx context.Context, c interface{}) (bool, error) {
+	if stopper, ok := c.(Stopper); ok {
+		return true, stopper.Stop(ctx)
+	} else if shutdowner, ok := c.(shutdowner); ok {
+		return true, shutdowner.Shutdown(ctx)
+	} else if closer, ok := c.(io.Closer); ok {
+		return true, closer.Close()
+	}
+	return false, nil
+}
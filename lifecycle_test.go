@@ -0,0 +1,265 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Recorder is a component which records each lifecycle method called on it into a shared log.
+type Recorder struct {
+	Name string
+	Log  *[]string
+}
+
+func (r *Recorder) Init() error {
+	*r.Log = append(*r.Log, "init "+r.Name)
+	return nil
+}
+
+func (r *Recorder) Start(ctx context.Context) error {
+	*r.Log = append(*r.Log, "start "+r.Name)
+	return nil
+}
+
+func (r *Recorder) Stop(ctx context.Context) error {
+	*r.Log = append(*r.Log, "stop "+r.Name)
+	return nil
+}
+
+// TestLifecycle tests that each phase can be run individually and in order.
+func TestLifecycle(t *testing.T) {
+	log := []string{}
+	components := struct {
+		First  *Recorder
+		Second *Recorder
+	}{
+		First:  &Recorder{Name: "first", Log: &log},
+		Second: &Recorder{Name: "second", Log: &log},
+	}
+	ctx := context.Background()
+
+	injector, err := Register("component", components)
+	assert.NoError(t, err)
+	assert.EqualError(t, injector.Init(), "simplewire init failed - must follow the wire phase, but the last phase was register")
+	assert.NoError(t, injector.Wire())
+	assert.Empty(t, log, "no lifecycle methods should be called by the wire phase")
+	assert.NoError(t, injector.Init())
+	assert.NoError(t, injector.Start(ctx))
+	assert.EqualError(t, injector.Start(ctx), "simplewire start failed - must follow the init phase, but the last phase was start")
+	assert.NoError(t, injector.Stop(ctx))
+	assert.Equal(t, []string{
+		"init first", "init second",
+		"start first", "start second",
+		"stop second", "stop first",
+	}, log)
+}
+
+// Disableable is a Recorder which can be disabled with ShouldInit.
+type Disableable struct {
+	Recorder
+	Enabled bool
+	DB      Database `component:"db"`
+}
+
+func (d *Disableable) ShouldInit() bool {
+	return d.Enabled
+}
+
+// TestConditionalInit tests that components can opt out of initialization while still being wired.
+func TestConditionalInit(t *testing.T) {
+	log := []string{}
+	components := struct {
+		DB       Database
+		Enabled  *Disableable
+		Disabled *Disableable
+	}{
+		DB:       &MockDB{},
+		Enabled:  &Disableable{Recorder: Recorder{Name: "enabled", Log: &log}, Enabled: true},
+		Disabled: &Disableable{Recorder: Recorder{Name: "disabled", Log: &log}},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"init enabled"}, log)
+	assert.Same(t, components.DB, components.Disabled.DB, "the disabled component should still be wired")
+	assert.Zero(t, injector.Graph().Components[2].InitDuration)
+
+	ctx := context.Background()
+	assert.NoError(t, injector.Start(ctx))
+	assert.NoError(t, injector.Stop(ctx))
+	assert.Equal(t, []string{"init enabled", "start enabled", "stop enabled"}, log, "the disabled component should not be started or stopped")
+}
+
+// Failing is a component whose Init always fails.
+type Failing struct {
+	Name string
+}
+
+func (f *Failing) Init() error {
+	return errors.New(f.Name + " failed")
+}
+
+// TestContinueOnInitError tests that every Init failure is collected when the injector continues past failures.
+func TestContinueOnInitError(t *testing.T) {
+	log := []string{}
+	components := struct {
+		First  *Failing
+		Middle *Recorder
+		Last   *Failing
+	}{
+		First:  &Failing{Name: "first"},
+		Middle: &Recorder{Name: "middle", Log: &log},
+		Last:   &Failing{Name: "last"},
+	}
+	_, err := Connect("component", components)
+	assert.EqualError(t, err, "simplewire init failed at First - first failed")
+	assert.Empty(t, log)
+
+	_, err = Connect("component", components, WithContinueOnInitError())
+	assert.EqualError(t, err, "simplewire init failed for 2 components: First - first failed; Last - last failed")
+	assert.Len(t, err, 2)
+	assert.Equal(t, []string{"init middle", "stop middle"}, log, "middle should be stopped by the rollback")
+}
+
+// TestRollback tests that a failed startup stops the components which were already initialized in reverse order.
+func TestRollback(t *testing.T) {
+	log := []string{}
+	components := struct {
+		First  *Recorder
+		Second *Recorder
+		Failed *Failing
+		Last   *Recorder
+	}{
+		First:  &Recorder{Name: "first", Log: &log},
+		Second: &Recorder{Name: "second", Log: &log},
+		Failed: &Failing{Name: "failed"},
+		Last:   &Recorder{Name: "last", Log: &log},
+	}
+	injector, err := Connect("component", components)
+	assert.EqualError(t, err, "simplewire init failed at Failed - failed failed")
+	initErr := &InitError{}
+	assert.True(t, errors.As(err, &initErr))
+	assert.Equal(t, "Failed", initErr.Component)
+	assert.Equal(t, []string{"init first", "init second", "stop second", "stop first"}, log)
+	assert.Error(t, injector.Stop(context.Background()), "the injector should already be stopped")
+}
+
+// OptionalConn is a connection which is closed rather than stopped, and can be disabled with ShouldInit.
+type OptionalConn struct {
+	Enabled bool
+	closed  bool
+}
+
+func (c *OptionalConn) ShouldInit() bool {
+	return c.Enabled
+}
+
+func (c *OptionalConn) Init() error {
+	return nil
+}
+
+func (c *OptionalConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestRollbackSkipsDisabled tests that components which ConditionalInit skipped are not closed by a rollback or by
+// the Stop phase.
+func TestRollbackSkipsDisabled(t *testing.T) {
+	components := struct {
+		Enabled  *OptionalConn
+		Disabled *OptionalConn
+		Failed   *Failing
+	}{
+		Enabled:  &OptionalConn{Enabled: true},
+		Disabled: &OptionalConn{},
+		Failed:   &Failing{Name: "failed"},
+	}
+	_, err := Connect("component", components)
+	assert.Error(t, err)
+	assert.True(t, components.Enabled.closed)
+	assert.False(t, components.Disabled.closed, "the disabled component was never initialized")
+
+	stopped := struct {
+		Enabled  *OptionalConn
+		Disabled *OptionalConn
+	}{
+		Enabled:  &OptionalConn{Enabled: true},
+		Disabled: &OptionalConn{},
+	}
+	injector, err := Connect("component", stopped)
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Stop(context.Background()))
+	assert.True(t, stopped.Enabled.closed)
+	assert.False(t, stopped.Disabled.closed, "the disabled component was never initialized")
+}
+
+// Parent and Child refer to each other, with the reference from Child to Parent marked weak.
+type Parent struct {
+	Child *Child `component:"child"`
+}
+
+type Child struct {
+	Parent *Parent `component:"parent,weak"`
+}
+
+// Tree and Leaf refer to each other, with the reference from Leaf to Tree marked weak, so Leaf must be initialized
+// first.
+type Tree struct {
+	Recorder
+	Leaf *Leaf `component:"leaf"`
+}
+
+type Leaf struct {
+	Recorder
+	Tree *Tree `component:"tree,weak"`
+}
+
+// TestWeakOrder tests that a cycle broken by a weak edge is initialized in the order of its other edges, and stopped
+// in the reverse order.
+func TestWeakOrder(t *testing.T) {
+	log := []string{}
+	components := struct {
+		First *Recorder
+		Tree  *Tree
+		Other *Recorder
+		Leaf  *Leaf
+	}{
+		First: &Recorder{Name: "first", Log: &log},
+		Tree:  &Tree{Recorder: Recorder{Name: "tree", Log: &log}},
+		Other: &Recorder{Name: "other", Log: &log},
+		Leaf:  &Leaf{Recorder: Recorder{Name: "leaf", Log: &log}},
+	}
+	injector, err := Connect("component", components, WithCycleCheck())
+	assert.NoError(t, err)
+	assert.Same(t, components.Tree, components.Leaf.Tree)
+	assert.Same(t, components.Leaf, components.Tree.Leaf)
+	assert.Equal(t, []string{"init first", "init leaf", "init other", "init tree"}, log,
+		"the leaf should be initialized before the tree, and the other components should not move")
+
+	log = log[:0]
+	assert.NoError(t, injector.Stop(context.Background()))
+	assert.Equal(t, []string{"stop tree", "stop other", "stop leaf", "stop first"}, log)
+}
+
+// TestCycleCheck tests that the Wire phase fails on a cycle of dependencies unless it is broken by a weak edge.
+func TestCycleCheck(t *testing.T) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	_, err := Connect("component", components)
+	assert.NoError(t, err, "cycles are allowed without the cycle check")
+	_, err = Connect("component", components, WithCycleCheck())
+	assert.EqualError(t, err, "simplewire wire failed - dependency cycle between Accounts, Users; add the weak option to a tag to allow it")
+
+	injector, err := Connect("component", struct {
+		Parent *Parent
+		Child  *Child
+	}{&Parent{}, &Child{}}, WithCycleCheck())
+	assert.NoError(t, err)
+	assert.Contains(t, injector.Graph().Edges, Edge{From: "Child", Field: "Parent", To: "Parent", Weak: true})
+}
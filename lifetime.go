@@ -0,0 +1,153 @@
+package simplewire
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Lifetime is how long an instance of a component lives, which decides how widely it is shared.
+//
+// A component may depend on components of any lifetime, except that a Scoped component can only be injected by a
+// child injector, since the root of the injectors is not a scope.  This keeps an instance which belongs to one
+// scope from being captured by a Singleton which outlives it.
+type Lifetime int
+
+const (
+	// Singleton components have a single instance which is shared by every consumer.  Every component is a
+	// Singleton unless it is declared with NewScoped or NewTransient.
+	Singleton Lifetime = iota
+	// Scoped components have one instance for each child injector, which is shared by the consumers within it.
+	// The instance is wired by the child the first time it is injected, and stopped with the child.  An instance
+	// built for a component of the child is initialized before that component, and otherwise when it is built.
+	Scoped
+	// Transient components have a new instance for each consumer.  Each instance is wired when it is injected.  An
+	// instance injected into a component is initialized just before that component in the Init phase, and otherwise
+	// when it is built.  Instances are not stopped by the injector, since they belong to their consumer.
+	Transient
+)
+
+var lifetimeNames = []string{"singleton", "scoped", "transient"}
+
+func (l Lifetime) String() string {
+	if l < 0 || int(l) >= len(lifetimeNames) {
+		return fmt.Sprintf("lifetime(%d)", int(l))
+	}
+	return lifetimeNames[l]
+}
+
+// Factory builds the instances of a component with a Scoped or Transient lifetime.  A Factory is placed in the
+// reference in place of the component, and the instances it builds are injected into fields of type T:
+//
+//	type Components struct {
+//		Session *simplewire.Factory[*Session]
+//	}
+//
+//	components := Components{Session: simplewire.NewScoped(newSession)}
+type Factory[T any] struct {
+	lifetime Lifetime
+	provide  func() (T, error)
+}
+
+// NewScoped creates a Factory for a component with one instance per child injector, built with provide.
+func NewScoped[T any](provide func() (T, error)) *Factory[T] {
+	return &Factory[T]{lifetime: Scoped, provide: provide}
+}
+
+// NewTransient creates a Factory for a component with a new instance for each consumer, built with provide.
+func NewTransient[T any](provide func() (T, error)) *Factory[T] {
+	return &Factory[T]{lifetime: Transient, provide: provide}
+}
+
+// Lifetime returns the lifetime of the instances the factory builds.
+func (f *Factory[T]) Lifetime() Lifetime {
+	return f.lifetime
+}
+
+func (f *Factory[T]) build() (interface{}, error) {
+	return f.provide()
+}
+
+// factory is implemented by every Factory so the injector can build instances without knowing their type parameter.
+type factory interface {
+	Lifetime() Lifetime
+	build() (interface{}, error)
+}
+
+// resolve returns the instance of the component named name, which is built by f, to inject into the field reached
+// by chain.
+func (i *injector) resolve(name string, f factory, chain trace) (interface{}, error) {
+	lname := strings.ToLower(name)
+	scoped := f.Lifetime() == Scoped
+	if scoped {
+		if i.parent == nil {
+			return nil, errorf(CodeLifetime, "%s is scoped and can only be injected by a child injector", name)
+		}
+		if !chain.scoping {
+			// the lock is only taken once along a chain, since the scoped components it builds may depend on each other
+			i.scopeLock.Lock()
+			defer i.scopeLock.Unlock()
+		}
+		if c, ok := i.scoped[lname]; ok {
+			return c.value, nil
+		}
+	}
+	if chain.isBuilding(lname) {
+		return nil, errorf(CodeCycle, "%s depends on itself", name)
+	}
+	chain = chain.build(lname, scoped)
+
+	v, err := f.build()
+	if err != nil {
+		return nil, errorf(CodeBuildFailed, "%s could not be built: %w", name, err)
+	}
+	c := &component{name: name, value: v}
+	if f.Lifetime() == Transient {
+		err = i.injectValue("", reflect.ValueOf(v), nil, chain)
+		if err != nil {
+			return nil, err
+		}
+		if i.wiring != nil {
+			// the instance is initialized in the Init phase, just before the component it was built for
+			i.wiring.transients = append(i.wiring.transients, c)
+			return v, nil
+		}
+		if !skipsInit(v) {
+			_, err = initialize(context.Background(), v)
+			if err != nil {
+				return nil, &InitError{Component: name, Err: err}
+			}
+		}
+		return v, nil
+	}
+
+	// a scoped instance becomes a component of the child, so it is initialized and stopped along with the others
+	i.scoped[lname] = c
+	consumer := i.wiring
+	if consumer != nil {
+		// the Transient instances the scoped instance depends on are initialized along with it
+		i.wiring = c
+	}
+	err = i.injectValue(name, reflect.ValueOf(v), nil, chain)
+	i.wiring = consumer
+	if err != nil {
+		delete(i.scoped, lname)
+		return nil, err
+	}
+	c.open = true
+	if consumer != nil {
+		// the instance is placed ahead of the component it was built for, so it is initialized first
+		i.wiredScoped = append(i.wiredScoped, c)
+		return v, nil
+	}
+	i.components = append(i.components, c)
+	if i.phase >= PhaseInit && i.phase < PhaseStop && !skipsInit(v) {
+		_, err = initialize(context.Background(), v)
+		if err != nil {
+			return nil, &InitError{Component: name, Err: err}
+		}
+		c.initialized = true
+	}
+	return v, nil
+}
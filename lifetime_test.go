@@ -0,0 +1,207 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Session is a component with one instance for each scope.
+type Session struct {
+	DB          Database `component:"db"`
+	initialized bool
+	stopped     bool
+}
+
+func (s *Session) Init() error {
+	s.initialized = true
+	return nil
+}
+
+func (s *Session) Stop(ctx context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+// Request is a component with a new instance for each consumer.
+type Request struct {
+	Session *Session `component:"session"`
+}
+
+// Handler consumes components of each lifetime.
+type Handler struct {
+	DB      Database `component:"db"`
+	Session *Session `component:"session"`
+	Request *Request `component:"request"`
+}
+
+// TestLifetimes tests that scoped components have one instance per child and transient components one per consumer.
+func TestLifetimes(t *testing.T) {
+	components := struct {
+		DB      Database
+		Session *Factory[*Session]
+		Request *Factory[*Request]
+	}{
+		DB:      &MockDB{},
+		Session: NewScoped(func() (*Session, error) { return &Session{}, nil }),
+		Request: NewTransient(func() (*Request, error) { return &Request{}, nil }),
+	}
+	root, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Equal(t, Scoped, components.Session.Lifetime())
+
+	scope := func() (*Handler, *Handler, Injector) {
+		handlers := struct{ First, Second *Handler }{&Handler{}, &Handler{}}
+		child, err := root.Child(handlers)
+		assert.NoError(t, err)
+		assert.NoError(t, child.Wire())
+		assert.NoError(t, child.Init())
+		return handlers.First, handlers.Second, child
+	}
+	first, second, child := scope()
+	assert.Same(t, components.DB, first.DB)
+	assert.Same(t, first.Session, second.Session, "a scoped component should be shared within a child")
+	assert.True(t, first.Session.initialized)
+	assert.Same(t, components.DB, first.Session.DB)
+	assert.NotSame(t, first.Request, second.Request, "a transient component should be built for each consumer")
+	assert.Same(t, first.Session, first.Request.Session)
+
+	other, _, _ := scope()
+	assert.NotSame(t, first.Session, other.Session, "each child should have its own scoped component")
+
+	assert.NoError(t, child.Stop(context.Background()))
+	assert.True(t, first.Session.stopped)
+	assert.False(t, other.Session.stopped)
+
+	err = root.Inject(&Handler{})
+	assert.EqualError(t, err, "simplewire inject failed at Request:Session - Session is scoped and can only be injected by a child injector - wiring chain: Inject(*simplewire.Handler) -> Handler.Request (Request)")
+}
+
+// TestConcurrentLifetimes tests that factories can be injected from several goroutines at once, building one scoped
+// instance for the child and a transient instance for each consumer.
+func TestConcurrentLifetimes(t *testing.T) {
+	components := struct {
+		DB      Database
+		Session *Factory[*Session]
+		Request *Factory[*Request]
+	}{
+		DB:      &MockDB{},
+		Session: NewScoped(func() (*Session, error) { return &Session{}, nil }),
+		Request: NewTransient(func() (*Request, error) { return &Request{}, nil }),
+	}
+	root, err := Connect("component", components)
+	assert.NoError(t, err)
+	child, err := root.Child(struct{}{})
+	assert.NoError(t, err)
+	assert.NoError(t, child.Wire())
+	assert.NoError(t, child.Init())
+
+	handlers := make([]*Handler, 8)
+	var wg sync.WaitGroup
+	for x := range handlers {
+		handlers[x] = &Handler{}
+		wg.Add(1)
+		go func(h *Handler) {
+			defer wg.Done()
+			assert.NoError(t, child.Inject(h))
+		}(handlers[x])
+	}
+	wg.Wait()
+	for _, h := range handlers[1:] {
+		assert.Same(t, handlers[0].Session, h.Session, "the child should build a single scoped instance")
+		assert.Same(t, h.Session, h.Request.Session)
+	}
+	assert.NoError(t, child.Stop(context.Background()))
+}
+
+// TestFactoryInitOrder tests that the instances of factories built during the Wire phase are initialized in the Init
+// phase, before the component they were built for, and after the components declared before it.
+func TestFactoryInitOrder(t *testing.T) {
+	type Query struct {
+		Recorder
+		DB *Recorder `component:"db"`
+	}
+	type Session struct {
+		Recorder
+		DB *Recorder `component:"db"`
+	}
+	type Handler struct {
+		Recorder
+		Query   *Query   `component:"query"`
+		Session *Session `component:"session"`
+	}
+	log := []string{}
+	components := struct {
+		DB      *Recorder
+		Query   *Factory[*Query]
+		Session *Factory[*Session]
+	}{
+		DB: &Recorder{Name: "db", Log: &log},
+		Query: NewTransient(func() (*Query, error) {
+			return &Query{Recorder: Recorder{Name: "query", Log: &log}}, nil
+		}),
+		Session: NewScoped(func() (*Session, error) {
+			return &Session{Recorder: Recorder{Name: "session", Log: &log}}, nil
+		}),
+	}
+	root, err := Register("component", components)
+	assert.NoError(t, err)
+	assert.NoError(t, root.Wire())
+	assert.Empty(t, log, "nothing should be initialized during the Wire phase")
+	assert.NoError(t, root.Init())
+	assert.Equal(t, []string{"init db"}, log)
+
+	log = log[:0]
+	handler := &Handler{Recorder: Recorder{Name: "handler", Log: &log}}
+	child, err := root.Child(struct{ Handler *Handler }{handler})
+	assert.NoError(t, err)
+	assert.NoError(t, child.Wire())
+	assert.Empty(t, log, "nothing should be initialized during the Wire phase")
+	assert.NoError(t, child.Init())
+	assert.Equal(t, []string{"init session", "init query", "init handler"}, log)
+	assert.Same(t, components.DB, handler.Query.DB)
+
+	log = log[:0]
+	assert.NoError(t, child.Stop(context.Background()))
+	assert.Equal(t, []string{"stop handler", "stop session"}, log, "the transient instance belongs to the handler")
+}
+
+// TestFactoryErrors tests that the errors of building and initializing the instances of factories can be unwrapped.
+func TestFactoryErrors(t *testing.T) {
+	buildErr := errors.New("cannot build")
+	type Consumer struct {
+		Failing *Failing `component:"failing"`
+	}
+	failing := NewTransient(func() (*Failing, error) { return &Failing{Name: "transient"}, nil })
+	injector, err := Connect("component", struct {
+		Failing *Factory[*Failing]
+		Broken  *Factory[*Failing]
+	}{
+		Failing: failing,
+		Broken:  NewTransient(func() (*Failing, error) { return nil, buildErr }),
+	})
+	assert.NoError(t, err)
+
+	err = injector.Inject(&Consumer{})
+	assert.EqualError(t, err, "simplewire inject failed at Consumer:Failing - simplewire init failed at Failing - transient failed")
+	var initErr *InitError
+	assert.True(t, errors.As(err, &initErr))
+	assert.Equal(t, "Failing", initErr.Component)
+	assert.Equal(t, CodeInitFailed, ErrorCode(err))
+
+	err = injector.Inject(&struct {
+		Broken *Failing `component:"broken"`
+	}{})
+	assert.True(t, errors.Is(err, buildErr))
+	assert.Equal(t, CodeBuildFailed, ErrorCode(err))
+
+	_, err = Connect("component", struct {
+		Failing  *Factory[*Failing]
+		Consumer *Consumer
+	}{failing, &Consumer{}})
+	assert.EqualError(t, err, "simplewire init failed at Failing - transient failed")
+	assert.True(t, errors.As(err, &initErr))
+}
@@ -0,0 +1,54 @@
+package simplewire
+
+// defaultMaxDepth is the most levels of nesting an injection may reach when WithLimits does not set MaxDepth.  It is
+// far deeper than any real graph, but stops a value which contains itself before it overflows the stack.
+const defaultMaxDepth = 100
+
+// Limits bounds the size of the graph an injector will build, so that a pathological reference, such as a module
+// which keeps providing new modules, fails quickly with a clear error instead of hanging or exhausting memory.  A
+// limit of zero means there is no limit, except for MaxDepth, which defaults to 100.
+type Limits struct {
+	// MaxComponents is the most components the Register phase accepts, including those provided by modules.
+	MaxComponents int
+	// MaxDepth is the most levels of nesting an injection may reach through the elements of slices, arrays, and maps
+	// and the components built by factories.
+	MaxDepth int
+	// MaxEdges is the most dependencies which may be injected between components.
+	MaxEdges int
+}
+
+// WithLimits bounds the size of the graph the injector will build.  An error with CodeLimitExceeded is returned by
+// the phase which exceeds a limit.
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
+
+// checkComponentLimit returns an error if n components are more than the limit.
+func (i *injector) checkComponentLimit(n int) error {
+	if limit := i.options.limits.MaxComponents; limit > 0 && n > limit {
+		return errorf(CodeLimitExceeded, "simplewire register failed - more than %d components, which is the limit", limit)
+	}
+	return nil
+}
+
+// checkDepth returns an error if the chain of injections is nested deeper than the limit.
+func (i *injector) checkDepth(chain trace) error {
+	limit := i.options.limits.MaxDepth
+	if limit <= 0 {
+		limit = defaultMaxDepth
+	}
+	if len(chain.steps)-1 > limit {
+		return errorf(CodeLimitExceeded, "simplewire inject failed - nested more than %d levels deep, which is the limit", limit)
+	}
+	return nil
+}
+
+// checkEdgeLimit returns an error if another edge would be more than the limit.
+func (i *injector) checkEdgeLimit() error {
+	if limit := i.options.limits.MaxEdges; limit > 0 && len(i.edges) >= limit {
+		return errorf(CodeLimitExceeded, "more than %d edges between components, which is the limit", limit)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package simplewire
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Endless is a module which provides another module, forever.
+type Endless struct {
+	n int
+}
+
+func (m Endless) Provide() map[string]interface{} {
+	return map[string]interface{}{
+		fmt.Sprintf("endless%d", m.n+1): Endless{m.n + 1},
+	}
+}
+
+// TestLimits tests that each limit stops a graph which is too large with a clear error.
+func TestLimits(t *testing.T) {
+	_, err := Connect("component", struct{ Endless Endless }{}, WithLimits(Limits{MaxComponents: 10}))
+	assert.EqualError(t, err, "simplewire register failed - more than 10 components, which is the limit")
+	assert.Equal(t, CodeLimitExceeded, ErrorCode(err))
+
+	_, err = Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}}, WithLimits(Limits{MaxEdges: 3}))
+	assert.EqualError(t, err, "simplewire inject failed at AccountsS:Users - more than 3 edges between components, which is the limit")
+	assert.Equal(t, CodeLimitExceeded, ErrorCode(err))
+	_, err = Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}}, WithLimits(Limits{MaxComponents: 3, MaxEdges: 4}))
+	assert.NoError(t, err)
+
+	injector, err := Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}}, WithLimits(Limits{MaxDepth: 2}))
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Inject([][]*Users{{{}}}))
+	err = injector.Inject([][][]*Users{{{{}}}})
+	assert.EqualError(t, err, "simplewire inject failed - nested more than 2 levels deep, which is the limit - wiring chain: Inject([][][]*simplewire.Users) -> [0] -> [0] -> [0]")
+	assert.Equal(t, CodeLimitExceeded, ErrorCode(err))
+}
+
+// TestSelfReferentialValue tests that a value which contains itself fails instead of overflowing the stack.
+func TestSelfReferentialValue(t *testing.T) {
+	injector, err := Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}})
+	assert.NoError(t, err)
+	values := []interface{}{nil}
+	values[0] = values
+	err = injector.Inject(values)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nested more than 100 levels deep")
+	assert.Equal(t, CodeLimitExceeded, ErrorCode(err))
+}
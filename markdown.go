@@ -0,0 +1,56 @@
+package simplewire
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes a markdown document describing each component of the graph in order, with its type, the
+// components it depends on, and the components which depend on it.  When describe is not nil, it is called with the
+// type of each component, such as "*app.Users", and the text it returns is included as the component's description.
+func (g Graph) WriteMarkdown(w io.Writer, describe func(typeName string) string) error {
+	dependsOn := map[string][]Edge{}
+	usedBy := map[string][]Edge{}
+	for _, e := range g.Edges {
+		dependsOn[e.From] = append(dependsOn[e.From], e)
+		usedBy[e.To] = append(usedBy[e.To], e)
+	}
+	var b strings.Builder
+	b.WriteString("# Components\n")
+	for _, c := range g.Components {
+		fmt.Fprintf(&b, "\n## %s\n\nType: `%s`\n", c.Name, c.Type)
+		if describe != nil {
+			if desc := strings.TrimSpace(describe(c.Type)); desc != "" {
+				fmt.Fprintf(&b, "\n%s\n", desc)
+			}
+		}
+		if edges := dependsOn[c.Name]; len(edges) > 0 {
+			b.WriteString("\nDepends on:\n\n")
+			for _, e := range edges {
+				fmt.Fprintf(&b, "* [%s](#%s) as `%s`%s\n", e.To, markdownAnchor(e.To), e.Field, weakNote(e))
+			}
+		}
+		if edges := usedBy[c.Name]; len(edges) > 0 {
+			b.WriteString("\nUsed by:\n\n")
+			for _, e := range edges {
+				fmt.Fprintf(&b, "* [%s](#%s) as `%s`%s\n", e.From, markdownAnchor(e.From), e.Field, weakNote(e))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// weakNote notes that an edge is weak.
+func weakNote(e Edge) string {
+	if e.Weak {
+		return " (weak)"
+	}
+	return ""
+}
+
+// markdownAnchor returns the anchor a heading of name is linked with, which is its lowercase text.
+func markdownAnchor(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+}
@@ -0,0 +1,34 @@
+package simplewire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteMarkdown tests that a markdown document describes each component and its dependencies.
+func TestWriteMarkdown(t *testing.T) {
+	g := Graph{
+		Components: []ComponentInfo{
+			{Name: "Users", Type: "*app.Users"},
+			{Name: "DB", Type: "*sql.DB"},
+		},
+		Edges: []Edge{
+			{From: "Users", Field: "DB", To: "DB"},
+		},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, g.WriteMarkdown(buf, func(typeName string) string {
+		if typeName == "*app.Users" {
+			return "Users manages the users of the application.\n"
+		}
+		return ""
+	}))
+	assert.Equal(t, "# Components\n"+
+		"\n## Users\n\nType: `*app.Users`\n"+
+		"\nUsers manages the users of the application.\n"+
+		"\nDepends on:\n\n* [DB](#db) as `DB`\n"+
+		"\n## DB\n\nType: `*sql.DB`\n"+
+		"\nUsed by:\n\n* [Users](#users) as `DB`\n", buf.String())
+}
@@ -0,0 +1,54 @@
+package simplewire
+
+import "reflect"
+
+// Optional is a field type for a dependency which might not exist.  When the named component is missing or nil,
+// the field is marked absent instead of failing to inject:
+//
+//	type Users struct {
+//		Cache simplewire.Optional[Cache] `inject:"cache"`
+//	}
+//
+//	if cache, ok := u.Cache.Get(); ok {
+//		...
+//	}
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Get returns the injected component and true, or the zero value and false if the component is absent.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+func (o *Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o *Optional[T]) setOptional(v reflect.Value) {
+	if !v.IsValid() {
+		*o = Optional[T]{}
+		return
+	}
+	o.value = v.Interface().(T)
+	o.ok = true
+}
+
+// optional is implemented by every Optional so the injector can fill them without knowing their type parameter.
+type optional interface {
+	// optionalType is the type of value the Optional holds.
+	optionalType() reflect.Type
+	// setOptional sets the value the Optional holds, or marks it absent when v is the zero Value.
+	setOptional(v reflect.Value)
+}
+
+var optionalInterfaceType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// asOptional returns the field as an optional if it is an Optional which can be set.
+func asOptional(field reflect.Value) optional {
+	if !field.CanAddr() || !field.Addr().CanInterface() || !field.Addr().Type().Implements(optionalInterfaceType) {
+		return nil
+	}
+	return field.Addr().Interface().(optional)
+}
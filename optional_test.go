@@ -0,0 +1,41 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOptional tests that optional fields are filled when the component exists and marked absent otherwise.
+func TestOptional(t *testing.T) {
+	components := struct {
+		DB    Database
+		Users *Users
+	}{
+		DB: &MockDB{},
+	}
+	injector, err := Register("component", components)
+	assert.NoError(t, err)
+
+	t1 := struct {
+		DB       Optional[Database] `component:"db"`
+		Users    Optional[*Users]   `component:"users"`
+		Accounts Optional[Accounts] `component:"accounts"`
+	}{}
+	t1.Users = Optional[*Users]{value: &Users{}, ok: true}
+	assert.NoError(t, injector.Inject(&t1))
+
+	db, ok := t1.DB.Get()
+	assert.True(t, ok, "db exists")
+	assert.Same(t, components.DB, db)
+	users, ok := t1.Users.Get()
+	assert.False(t, ok, "users is nil")
+	assert.Nil(t, users)
+	_, ok = t1.Accounts.Get()
+	assert.False(t, ok, "accounts does not exist")
+
+	t2 := struct {
+		DB Optional[*Users] `component:"db"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t2), "simplewire inject failed at anonymous struct:DB - *simplewire.MockDB is not assignable to *simplewire.Users")
+}
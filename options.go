@@ -0,0 +1,99 @@
+package simplewire
+
+import (
+	"reflect"
+	"time"
+)
+
+// Option configures the behavior of an Injector.  Options are passed to Connect or Register.
+type Option func(*options)
+
+type options struct {
+	continueOnInitError bool
+	eventLog            *eventLog
+	errorHook           func(err error, component, field string)
+	cycleCheck          bool
+	tagKeys             []string
+	stub                func(t reflect.Type) interface{}
+	leakTracking        bool
+	buildInfo           bool
+	startupBudget       time.Duration
+	strictInterfaces    bool
+	clock               Clock
+	bridges             []*Bridge
+	accessChecks        bool
+	drainTimeout        time.Duration
+	limits              Limits
+	connectOnce         bool
+	// wirings holds the tag of each field declared with WithWiring, keyed by the struct type
+	wirings    map[reflect.Type]map[string]string
+	wiringErrs []error
+	// labels are keyed by the lowercase name of the component
+	labels map[string]map[string]string
+}
+
+// newOptions applies each of opts on top of o.
+func newOptions(o options, opts []Option) options {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.clock == nil {
+		o.clock = SystemClock
+	}
+	return o
+}
+
+// WithContinueOnInitError makes the Init phase keep initializing the remaining components after one fails.  Every
+// failure is collected and returned together as InitErrors, which is useful for tooling and diagnostics that want
+// the full picture rather than the first error.
+func WithContinueOnInitError() Option {
+	return func(o *options) {
+		o.continueOnInitError = true
+	}
+}
+
+// WithCycleCheck makes the Wire phase fail when components depend on each other in a cycle.  A dependency which is
+// intentionally cyclic can be exempted by adding the weak option to its tag, such as `inject:"users,weak"`.  The
+// components of a cycle broken by a weak dependency are initialized in the order of their other dependencies, so a
+// weak dependency may not have been initialized yet when the component holding it is.
+func WithCycleCheck() Option {
+	return func(o *options) {
+		o.cycleCheck = true
+	}
+}
+
+// WithTagKeys adds more struct tag keys to check for each field which does not have the tag given to Connect or
+// Register.  The keys are checked in order and the first one the field has is used, which eases migrating code that
+// is tagged for another dependency injection library.
+func WithTagKeys(keys ...string) Option {
+	return func(o *options) {
+		o.tagKeys = append(append([]string{}, o.tagKeys...), keys...)
+	}
+}
+
+// WithStubs is meant for tests of a single component.  When a field of an interface type names a component which
+// does not exist, stub is called with the interface type, and the value it returns is injected instead.  If stub
+// returns nil, the field fails to be injected as usual.  Stubbed fields are not recorded as edges of the graph.
+func WithStubs(stub func(t reflect.Type) interface{}) Option {
+	return func(o *options) {
+		o.stub = stub
+	}
+}
+
+// WithErrorHook calls hook with each error from wiring or from a lifecycle method, before the error is returned.  The
+// component is the name of the component or the type of the destination given to Inject, and the field is empty
+// unless the error is from wiring a field.  Hooks can be used to emit metrics or send alerts uniformly.  The hook is
+// also told when a deprecated component is injected, with an error whose code is CodeDeprecated, which does not fail
+// the injection.
+func WithErrorHook(hook func(err error, component, field string)) Option {
+	return func(o *options) {
+		o.errorHook = hook
+	}
+}
+
+// reportError calls the error hook, if the injector has one.
+func (i *injector) reportError(err error, component, field string) {
+	if i.options.errorHook != nil {
+		i.options.errorHook(err, component, field)
+	}
+}
@@ -0,0 +1,58 @@
+package simplewire
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorHook tests that the error hook is called for wiring and init errors before they are returned.
+func TestErrorHook(t *testing.T) {
+	type hooked struct {
+		err              string
+		component, field string
+	}
+	calls := []hooked{}
+	hook := WithErrorHook(func(err error, component, field string) {
+		calls = append(calls, hooked{err.Error(), component, field})
+	})
+
+	_, err := Connect("component", struct{ Failed *Failing }{&Failing{Name: "failed"}}, hook)
+	assert.Error(t, err)
+	injector, err := Connect("component", struct{ DB Database }{&MockDB{}}, hook)
+	assert.NoError(t, err)
+	assert.Error(t, injector.Inject([]*Users{{}}))
+
+	assert.Equal(t, []hooked{
+		{"failed failed", "Failed", ""},
+		{"simplewire inject failed at Users:Accounts - accounts not found in reference struct - wiring chain: Inject([]*simplewire.Users) -> [0]", "Users", "Accounts"},
+	}, calls)
+}
+
+// TestStubs tests that interface fields naming missing components are injected with stubs.
+func TestStubs(t *testing.T) {
+	stubDB := &MockDB{}
+	stubs := WithStubs(func(t reflect.Type) interface{} {
+		if t == reflect.TypeOf((*Database)(nil)).Elem() {
+			return stubDB
+		}
+		return nil
+	})
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+	}{&Users{}, &AccountsS{}}
+	injector, err := Connect("component", components, stubs)
+	assert.NoError(t, err)
+	assert.Same(t, stubDB, components.Users.DB)
+	assert.Same(t, stubDB, components.Accounts.(*AccountsS).DB)
+	assert.Equal(t, []Edge{
+		{From: "Users", Field: "Accounts", To: "Accounts"},
+		{From: "Accounts", Field: "Users", To: "Users"},
+	}, injector.Graph().Edges, "stubbed fields should not be edges")
+
+	// only the types the stub function knows are stubbed
+	_, err = Connect("component", struct{ Users *Users }{&Users{}}, stubs)
+	assert.EqualError(t, err, "simplewire inject failed at Users:Accounts - accounts not found in reference struct")
+}
@@ -0,0 +1,69 @@
+package simplewire
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Plan is a compiled injection for one destination type, made by Compile.  Applying a plan only sets the fields of
+// the destination to the values found when it was compiled, without parsing tags, finding components, or checking
+// types again, so it suits hot paths which inject the same small type for every request.
+type Plan struct {
+	typ   reflect.Type
+	steps []planStep
+}
+
+// planStep sets the field at index to value.
+type planStep struct {
+	index int
+	value reflect.Value
+}
+
+// Compile injects a new value of the struct type dest points to, the same way as Inject but without calling Init,
+// and returns a Plan which sets the same values on other destinations of that type.  Fields injected with a
+// Transient component, including by label, cannot be compiled, since each destination needs its own instance.  A plan
+// does not see components swapped by Replace after it was compiled, so it should be compiled again.
+func (i *injector) Compile(dest interface{}) (*Plan, error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errorf(CodeInvalidDestination, "simplewire compile failed - destination must be a pointer to a struct, not %T", dest)
+	}
+	t := v.Elem().Type()
+	fields := i.destFields(dest, t)
+	for _, field := range fields {
+		refs := []string{field.ref}
+		if kv := strings.SplitN(field.ref, "=", 2); field.opts.labeled && len(kv) == 2 {
+			refs = i.labeledNames(kv[0], kv[1])
+		}
+		for _, name := range refs {
+			_, ref, err := i.getRefFieldByName(name)
+			if f, ok := ref.(factory); err == nil && ok && f.Lifetime() == Transient {
+				return nil, errorf(CodeLifetime, "simplewire compile failed - %s.%s is injected with %s, which is transient", structName(t), field.name, name)
+			}
+		}
+	}
+	sample := reflect.New(t)
+	err := i.injectValue("", sample, nil, trace{})
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{typ: t, steps: make([]planStep, 0, len(fields))}
+	for _, field := range fields {
+		plan.steps = append(plan.steps, planStep{index: field.index, value: sample.Elem().Field(field.index)})
+	}
+	return plan, nil
+}
+
+// Apply sets the injected fields of dest, which must be a pointer to the type the plan was compiled for.  Init is not
+// called.
+func (p *Plan) Apply(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Type().Elem() != p.typ || v.IsNil() {
+		return errorf(CodeInvalidDestination, "simplewire apply failed - the plan is for *%s, not %T", p.typ, dest)
+	}
+	e := v.Elem()
+	for _, s := range p.steps {
+		e.Field(s.index).Set(s.value)
+	}
+	return nil
+}
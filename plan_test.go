@@ -0,0 +1,134 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ReqDeps is a destination injected for each request.
+type ReqDeps struct {
+	DB       Database           `component:"db"`
+	Users    *Users             `component:"users"`
+	Config   Config             `component:"config,value"`
+	Audit    Optional[Accounts] `component:"audit"`
+	Session  *Session           `component:"session"`
+	internal int
+}
+
+// TestPlan tests that a compiled plan sets the same fields as Inject, without allocating.
+func TestPlan(t *testing.T) {
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+		Config   Config
+		Session  *Factory[*Session]
+	}{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+		Config:   Config{Name: "app"},
+		Session:  NewScoped(func() (*Session, error) { return &Session{}, nil }),
+	}
+	parent, err := Connect("component", components)
+	assert.NoError(t, err)
+	injector, err := parent.Child(struct{}{})
+	assert.NoError(t, err)
+
+	plan, err := injector.Compile(&ReqDeps{})
+	assert.NoError(t, err)
+	deps := ReqDeps{internal: 1}
+	assert.NoError(t, plan.Apply(&deps))
+	assert.Same(t, components.DB, deps.DB)
+	assert.Same(t, components.Users, deps.Users)
+	assert.Equal(t, components.Config, deps.Config)
+	_, ok := deps.Audit.Get()
+	assert.False(t, ok)
+	assert.NotNil(t, deps.Session)
+	assert.Equal(t, 1, deps.internal, "fields which are not injected should be left alone")
+
+	injected := ReqDeps{internal: 1}
+	assert.NoError(t, injector.Inject(&injected))
+	assert.Equal(t, injected, deps, "a plan should set the same values as Inject")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = plan.Apply(&deps)
+	})
+	assert.Zero(t, allocs)
+
+	err = plan.Apply(&Users{})
+	assert.EqualError(t, err, "simplewire apply failed - the plan is for *simplewire.ReqDeps, not *simplewire.Users")
+	assert.Equal(t, CodeInvalidDestination, ErrorCode(err))
+	_, err = injector.Compile(ReqDeps{})
+	assert.EqualError(t, err, "simplewire compile failed - destination must be a pointer to a struct, not simplewire.ReqDeps")
+}
+
+// TestPlanErrors tests that a destination which cannot be injected, or which needs a new transient for each value,
+// cannot be compiled.
+func TestPlanErrors(t *testing.T) {
+	injector, err := Connect("component", struct {
+		DB      Database
+		Request *Factory[*Request]
+	}{&MockDB{}, NewTransient(func() (*Request, error) { return &Request{}, nil })})
+	assert.NoError(t, err)
+
+	_, err = injector.Compile(&struct {
+		Request *Request `component:"request"`
+	}{})
+	assert.EqualError(t, err, "simplewire compile failed - anonymous struct.Request is injected with request, which is transient")
+	assert.Equal(t, CodeLifetime, ErrorCode(err))
+
+	_, err = injector.Compile(&struct {
+		Users *Users `component:"users"`
+	}{})
+	assert.EqualError(t, err, "simplewire inject failed at anonymous struct:Users - users not found in reference struct")
+}
+
+// planInjector returns a child injector which can inject ReqDeps.
+func planInjector(tb testing.TB) Injector {
+	parent, err := Connect("component", struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+		Config   Config
+		Session  *Factory[*Session]
+	}{&Users{}, &AccountsS{}, &MockDB{}, Config{Name: "app"}, NewScoped(func() (*Session, error) { return &Session{}, nil })})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	injector, err := parent.Child(struct{}{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return injector
+}
+
+// BenchmarkPlanApply and BenchmarkPlanInject compare applying a compiled plan with injecting the same type.
+func BenchmarkPlanApply(b *testing.B) {
+	injector := planInjector(b)
+	dest := &ReqDeps{}
+	plan, err := injector.Compile(dest)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		if err := plan.Apply(dest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPlanInject(b *testing.B) {
+	injector := planInjector(b)
+	dest := &ReqDeps{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		if err := injector.Inject(dest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package simplewire
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pool holds interchangeable instances of a component which is too expensive to build for every use, but cannot be
+// shared as a single instance.  A Pool is placed in the reference and injected like any other component:
+//
+//	type Components struct {
+//		Shards *simplewire.Pool[*ShardClient]
+//	}
+//
+// During the Init phase, the pool is filled with its minimum number of instances.  More are built on demand, up to
+// the maximum, and Acquire waits for an instance to be released once the maximum is reached.  During the Stop phase,
+// every idle instance which implements Stopper or io.Closer is stopped.
+type Pool[T any] struct {
+	provide func() (T, error)
+	min     int
+	idle    chan T
+
+	mu   sync.Mutex
+	size int
+}
+
+// NewPool creates a pool which uses provide to build instances.  NewPool panics unless 0 <= min <= max and max > 0.
+func NewPool[T any](min, max int, provide func() (T, error)) *Pool[T] {
+	if min < 0 || max < 1 || min > max {
+		panic(fmt.Sprintf("simplewire pool size must satisfy 0 <= min <= max and max > 0, but min is %d and max is %d", min, max))
+	}
+	return &Pool[T]{
+		provide: provide,
+		min:     min,
+		idle:    make(chan T, max),
+	}
+}
+
+// Init fills the pool with its minimum number of instances.  If an instance cannot be built, the instances which
+// were built already are stopped, and the pool is left empty.
+func (p *Pool[T]) Init() error {
+	built := []T{}
+	for x := 0; x < p.min; x++ {
+		v, ok, err := p.grow()
+		if err != nil {
+			for _, v := range built {
+				p.discard(v)
+			}
+			return err
+		} else if !ok {
+			break
+		}
+		built = append(built, v)
+	}
+	for _, v := range built {
+		p.idle <- v
+	}
+	return nil
+}
+
+// Acquire takes an instance from the pool, building a new one if none are idle and the pool is not full.  If the
+// pool is full, Acquire waits until an instance is released or ctx is done.  Every instance which is acquired must
+// be given back with Release.
+func (p *Pool[T]) Acquire(ctx context.Context) (T, error) {
+	select {
+	case v := <-p.idle:
+		return v, nil
+	default:
+	}
+	v, ok, err := p.grow()
+	if ok || err != nil {
+		return v, err
+	}
+	select {
+	case v := <-p.idle:
+		return v, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Release gives an instance back to the pool.  An instance which does not fit because the pool already holds its
+// maximum, such as one which was not acquired from it, is stopped rather than kept, so Release never blocks.
+func (p *Pool[T]) Release(v T) {
+	select {
+	case p.idle <- v:
+	default:
+		_, _ = stopComponent(context.Background(), v)
+	}
+}
+
+// Stop stops every idle instance which implements Stopper or io.Closer.  Instances which are still acquired are not
+// stopped.  Every instance is stopped even if some fail, in which case the first error is returned.
+func (p *Pool[T]) Stop(ctx context.Context) error {
+	var firstErr error
+	for {
+		select {
+		case v := <-p.idle:
+			p.mu.Lock()
+			p.size--
+			p.mu.Unlock()
+			_, err := stopComponent(ctx, v)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		default:
+			return firstErr
+		}
+	}
+}
+
+// discard stops an instance which was built by grow, making room for another.
+func (p *Pool[T]) discard(v T) {
+	p.mu.Lock()
+	p.size--
+	p.mu.Unlock()
+	_, _ = stopComponent(context.Background(), v)
+}
+
+// grow builds a new instance unless the pool is already full, reporting whether an instance was built.
+func (p *Pool[T]) grow() (T, bool, error) {
+	var zero T
+	p.mu.Lock()
+	if p.size == cap(p.idle) {
+		p.mu.Unlock()
+		return zero, false, nil
+	}
+	p.size++
+	p.mu.Unlock()
+
+	v, err := p.provide()
+	if err != nil {
+		p.mu.Lock()
+		p.size--
+		p.mu.Unlock()
+		return zero, false, err
+	}
+	return v, true, nil
+}
@@ -0,0 +1,104 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ShardClient is an expensive component which is pooled.
+type ShardClient struct {
+	ID     int
+	closed bool
+}
+
+func (c *ShardClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestPool tests that a pool can be injected, is filled during Init, grows to its maximum, and is stopped.
+func TestPool(t *testing.T) {
+	built := []*ShardClient{}
+	components := struct {
+		Shards *Pool[*ShardClient]
+	}{
+		Shards: NewPool(1, 2, func() (*ShardClient, error) {
+			c := &ShardClient{ID: len(built)}
+			built = append(built, c)
+			return c, nil
+		}),
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Len(t, built, 1, "the pool should be filled to its minimum during Init")
+
+	t1 := struct {
+		Shards *Pool[*ShardClient] `component:"shards"`
+	}{}
+	assert.NoError(t, injector.Inject(&t1))
+	assert.Same(t, components.Shards, t1.Shards)
+
+	ctx := context.Background()
+	first, err := t1.Shards.Acquire(ctx)
+	assert.NoError(t, err)
+	second, err := t1.Shards.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, second)
+	assert.Len(t, built, 2)
+
+	// the pool is full, so a third Acquire waits until the context is done
+	timeout, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err = t1.Shards.Acquire(timeout)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	t1.Shards.Release(first)
+	again, err := t1.Shards.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.Same(t, first, again)
+
+	t1.Shards.Release(first)
+	t1.Shards.Release(second)
+	assert.NoError(t, injector.Stop(ctx))
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+
+	assert.Panics(t, func() { NewPool(2, 1, func() (int, error) { return 0, nil }) })
+}
+
+// TestPoolOverflow tests that releasing more instances than the pool holds stops the extra ones instead of blocking.
+func TestPoolOverflow(t *testing.T) {
+	pool := NewPool(0, 1, func() (*ShardClient, error) { return &ShardClient{}, nil })
+	ctx := context.Background()
+	first, err := pool.Acquire(ctx)
+	assert.NoError(t, err)
+	pool.Release(first)
+
+	extra := &ShardClient{ID: 1}
+	pool.Release(extra)
+	assert.True(t, extra.closed, "an instance which does not fit should be closed")
+	assert.False(t, first.closed)
+}
+
+// TestPoolInitFailure tests that the instances which were built are closed when the pool cannot be filled.
+func TestPoolInitFailure(t *testing.T) {
+	built := []*ShardClient{}
+	pool := NewPool(3, 3, func() (*ShardClient, error) {
+		if len(built) == 2 {
+			return nil, errors.New("shard unavailable")
+		}
+		c := &ShardClient{ID: len(built)}
+		built = append(built, c)
+		return c, nil
+	})
+	assert.EqualError(t, pool.Init(), "shard unavailable")
+	assert.Len(t, built, 2)
+	assert.True(t, built[0].closed)
+	assert.True(t, built[1].closed)
+	assert.Zero(t, pool.size, "the pool should be left empty")
+	assert.Empty(t, pool.idle)
+}
@@ -0,0 +1,178 @@
+package simplewire
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Preloader can be implemented by a component with work that is worth doing before taking traffic but is not needed
+// to initialize it, such as filling a cache or opening a pool of connections.
+type Preloader interface {
+	Preload(ctx context.Context) error
+}
+
+// Preload warms the named components, or every component when no names are given, and waits for them to finish.
+// First, the components declared with a Factory are built concurrently: a Scoped instance is built, wired, and
+// initialized by a child injector just as if it had been injected, and a Transient instance is built, wired,
+// initialized, and then stopped, since each consumer gets its own.  Scoped components are skipped when no names are
+// given to an injector which is not a child.  Then Preload is called concurrently on the components which implement
+// Preloader, including the Scoped instances which were just built.  Preload must follow the Init or Start phase, so
+// every dependency of the components has been initialized.  The errors of every component which failed are returned
+// together.
+func (i *injector) Preload(ctx context.Context, names ...string) error {
+	if err := i.checkNotSubset("preload"); err != nil {
+		return err
+	}
+	if i.phase != PhaseInit && i.phase != PhaseStart {
+		return errorf(CodeWrongPhase, "simplewire preload failed - must follow the init or start phase, but the last phase was %s", i.phase)
+	}
+	build := []namedFactory{}
+	preload := []*component{}
+	if len(names) == 0 {
+		build = i.factories()
+	}
+	for _, name := range names {
+		if refName, value, err := i.getRefFieldByName(name); err == nil {
+			if f, ok := value.(factory); ok {
+				build = append(build, namedFactory{name: refName, f: f})
+				continue
+			}
+		}
+		var found *component
+		for _, c := range i.components {
+			if strings.EqualFold(c.name, name) {
+				found = c
+				break
+			}
+		}
+		if found == nil {
+			return errorf(CodeNotFound, "simplewire preload failed - %s not found in reference struct", name)
+		} else if _, ok := found.value.(Preloader); !ok {
+			return errorf(CodeNotImplemented, "simplewire preload failed - %s does not implement Preloader or have a Factory", found.name)
+		}
+		preload = append(preload, found)
+	}
+
+	built, errs := i.buildFactories(ctx, build)
+	if len(names) == 0 {
+		// the Scoped instances which were built are components now, so they are found here too
+		for _, c := range i.components {
+			if _, ok := c.value.(Preloader); ok {
+				preload = append(preload, c)
+			}
+		}
+	} else {
+		for _, c := range built {
+			if _, ok := c.value.(Preloader); ok {
+				preload = append(preload, c)
+			}
+		}
+	}
+
+	preloadErrs := make([]error, len(preload))
+	var wg sync.WaitGroup
+	for x, c := range preload {
+		wg.Add(1)
+		go func(x int, c *component) {
+			defer wg.Done()
+			start := i.now()
+			err := c.value.(Preloader).Preload(ctx)
+			i.emitTimed("preload", i.phase, c.name, start, err)
+			if err != nil {
+				i.reportError(err, c.name, "")
+				preloadErrs[x] = errorf(CodePreloadFailed, "simplewire preload failed at %s - %w", c.name, err)
+			}
+		}(x, c)
+	}
+	wg.Wait()
+	for _, err := range preloadErrs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	} else if len(errs) > 1 {
+		return PreloadErrors(errs)
+	}
+	return nil
+}
+
+// PreloadErrors is returned by Preload when more than one component fails, holding the error of each.
+type PreloadErrors []error
+
+func (e PreloadErrors) Error() string {
+	msgs := make([]string, len(e))
+	for x, err := range e {
+		msgs[x] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// namedFactory is a component declared with a Factory.
+type namedFactory struct {
+	name string
+	f    factory
+}
+
+// factories returns the components declared with a Factory which the injector can build, including those of its
+// parents, in the order they are declared.  Scoped components are left out unless the injector is a child.
+func (i *injector) factories() []namedFactory {
+	found := []namedFactory{}
+	seen := map[string]bool{}
+	for from := i; from != nil; from = from.parent {
+		for _, c := range from.components {
+			f, ok := c.value.(factory)
+			if !ok || seen[strings.ToLower(c.name)] || (f.Lifetime() == Scoped && i.parent == nil) {
+				continue
+			}
+			seen[strings.ToLower(c.name)] = true
+			found = append(found, namedFactory{name: c.name, f: f})
+		}
+	}
+	return found
+}
+
+// buildFactories builds an instance of each component concurrently, the way it is built when it is injected.  The
+// Scoped instances are returned as the components of the injector they became, and Transient instances are stopped
+// once they are built.  The error of each component which failed is returned.
+func (i *injector) buildFactories(ctx context.Context, build []namedFactory) ([]*component, []error) {
+	built := make([]*component, len(build))
+	errs := make([]error, len(build))
+	var wg sync.WaitGroup
+	for x, nf := range build {
+		wg.Add(1)
+		go func(x int, nf namedFactory) {
+			defer wg.Done()
+			start := i.now()
+			v, err := i.resolve(nf.name, nf.f, trace{steps: []string{"Preload(" + nf.name + ")"}})
+			if err == nil && nf.f.Lifetime() == Transient {
+				_, err = stopComponent(ctx, v)
+			} else if err == nil {
+				i.scopeLock.Lock()
+				built[x] = i.scoped[strings.ToLower(nf.name)]
+				i.scopeLock.Unlock()
+			}
+			i.emitTimed("preload", i.phase, nf.name, start, err)
+			if err != nil {
+				i.reportError(err, nf.name, "")
+				errs[x] = errorf(CodePreloadFailed, "simplewire preload failed at %s - %w", nf.name, err)
+			}
+		}(x, nf)
+	}
+	wg.Wait()
+	scoped := []*component{}
+	for _, c := range built {
+		if c != nil {
+			scoped = append(scoped, c)
+		}
+	}
+	failed := []error{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return scoped, failed
+}
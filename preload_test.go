@@ -0,0 +1,114 @@
+package simplewire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Cache is a component which waits for another Cache to begin preloading, so it can only finish when they are
+// preloaded concurrently.
+type Cache struct {
+	started chan struct{}
+	other   *Cache
+	err     error
+}
+
+func (c *Cache) Preload(ctx context.Context) error {
+	close(c.started)
+	select {
+	case <-c.other.started:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TestPreload tests that components are preloaded concurrently and that their errors are returned together.
+func TestPreload(t *testing.T) {
+	first := &Cache{started: make(chan struct{})}
+	second := &Cache{started: make(chan struct{}), other: first, err: errors.New("cache unavailable")}
+	first.other = second
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	injector, err := Register("component", struct {
+		First  *Cache
+		Second *Cache
+		DB     Database
+	}{first, second, &MockDB{}})
+	assert.NoError(t, err)
+	assert.EqualError(t, injector.Preload(ctx), "simplewire preload failed - must follow the init or start phase, but the last phase was register")
+	assert.NoError(t, injector.Wire())
+	assert.NoError(t, injector.Init())
+
+	assert.EqualError(t, injector.Preload(ctx, "db"), "simplewire preload failed - DB does not implement Preloader or have a Factory")
+	assert.EqualError(t, injector.Preload(ctx, "missing"), "simplewire preload failed - missing not found in reference struct")
+	assert.EqualError(t, injector.Preload(ctx), "simplewire preload failed at Second - cache unavailable")
+}
+
+// WarmSession is a scoped component which records that it was preloaded.
+type WarmSession struct {
+	Session
+	preloaded bool
+}
+
+func (s *WarmSession) Preload(ctx context.Context) error {
+	s.preloaded = true
+	return nil
+}
+
+// TestPreloadFactories tests that Preload builds the components declared with a Factory before they are injected.
+func TestPreloadFactories(t *testing.T) {
+	ctx := context.Background()
+	type Query struct {
+		DB Database `component:"db"`
+	}
+	sessions := []*WarmSession{}
+	queries := 0
+	root, err := Connect("component", struct {
+		DB      Database
+		Session *Factory[*WarmSession]
+		Query   *Factory[*Query]
+	}{
+		DB: &MockDB{},
+		Session: NewScoped(func() (*WarmSession, error) {
+			s := &WarmSession{}
+			sessions = append(sessions, s)
+			return s, nil
+		}),
+		Query: NewTransient(func() (*Query, error) {
+			queries++
+			return &Query{}, nil
+		}),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, root.Preload(ctx), "scoped components should be skipped by an injector which is not a child")
+	assert.Empty(t, sessions)
+	assert.Equal(t, 1, queries, "the transient provider should be exercised once")
+	err = root.Preload(ctx, "session")
+	assert.EqualError(t, err, "simplewire preload failed at Session - Session is scoped and can only be injected by a child injector")
+	assert.Equal(t, CodePreloadFailed, ErrorCode(err))
+
+	child, err := root.Child(struct{}{})
+	assert.NoError(t, err)
+	assert.NoError(t, child.Wire())
+	assert.NoError(t, child.Init())
+	assert.NoError(t, child.Preload(ctx, "session"))
+	if assert.Len(t, sessions, 1) {
+		assert.True(t, sessions[0].initialized, "the scoped instance should be initialized")
+		assert.True(t, sessions[0].preloaded, "the scoped instance should be preloaded")
+	}
+
+	handler := struct {
+		Session *WarmSession `component:"session"`
+	}{}
+	assert.NoError(t, child.Inject(&handler))
+	assert.Len(t, sessions, 1, "the preloaded instance should be injected")
+	assert.Same(t, sessions[0], handler.Session)
+	assert.NoError(t, child.Stop(ctx))
+	assert.True(t, sessions[0].stopped)
+}
@@ -0,0 +1,30 @@
+package simplewire
+
+// Reference returns the reference the injector was created with, as it was given to Connect, Register, or Child, so
+// code which only holds the injector can reach the components, such as to describe them in an admin endpoint.  The
+// reference should only be read; components replaced with Replace are not reflected in it.  A subset returns nil,
+// since it only exposes some of the components.
+func (i *injector) Reference() interface{} {
+	if i.allowed != nil {
+		return nil
+	}
+	return i.original
+}
+
+// ReferenceOf returns the reference of the injector as the type T, which may be the type the reference was given
+// as, or the struct type when the reference was given as a pointer to it, in which case a copy is returned.  False
+// is returned if the reference is not a T.
+//
+//	components, ok := simplewire.ReferenceOf[*Components](injector)
+func ReferenceOf[T any](inj Injector) (T, bool) {
+	switch ref := inj.Reference().(type) {
+	case T:
+		return ref, true
+	case *T:
+		if ref != nil {
+			return *ref, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,38 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReference tests that the reference can be reached from the injector, typed as it was given or as its struct.
+func TestReference(t *testing.T) {
+	components := &Components{&Users{}, &AccountsS{}, &MockDB{}}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Same(t, components, injector.Reference())
+
+	ref, ok := ReferenceOf[*Components](injector)
+	assert.True(t, ok)
+	assert.Same(t, components, ref)
+	copied, ok := ReferenceOf[Components](injector)
+	assert.True(t, ok)
+	assert.Same(t, components.Users, copied.Users)
+	_, ok = ReferenceOf[Config](injector)
+	assert.False(t, ok)
+
+	byValue, err := Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}})
+	assert.NoError(t, err)
+	copied, ok = ReferenceOf[Components](byValue)
+	assert.True(t, ok)
+	assert.NotNil(t, copied.DB)
+	_, ok = ReferenceOf[*Components](byValue)
+	assert.False(t, ok)
+
+	subset, err := injector.Subset("accounts")
+	assert.NoError(t, err)
+	assert.Nil(t, subset.Reference(), "a subset should not expose the whole reference")
+	_, ok = ReferenceOf[*Components](subset)
+	assert.False(t, ok)
+}
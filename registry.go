@@ -0,0 +1,83 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Registry is a set of named components which can be used in place of a reference struct.  It lets each package
+// declare its own components, typically from a function which returns a new Registry, without a shared struct that
+// lists every component of the application or any global state.  Registries are combined with Merge and passed to
+// Connect or Register as the reference.
+//
+//	injector, err := simplewire.Connect("inject", simplewire.Merge(accounts.Registry(), billing.Registry()))
+//
+// Components are wired and initialized in the order they were added.  A Registry also implements Module, so it may
+// be a field of a reference struct.
+type Registry struct {
+	names      []string
+	components map[string]interface{}
+	err        error
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{components: map[string]interface{}{}}
+}
+
+// Add adds a component to the registry with the name it will be injected with.  Names are matched the same way as
+// the field names of a reference, so adding two components whose names differ only by case is an error, which is
+// reported when the registry is connected.
+func (r *Registry) Add(name string, component interface{}) *Registry {
+	if r.err == nil {
+		if _, ok := r.components[strings.ToLower(name)]; ok {
+			r.err = fmt.Errorf("%s is registered more than once", name)
+		}
+	}
+	r.names = append(r.names, name)
+	r.components[strings.ToLower(name)] = component
+	return r
+}
+
+// Merge returns a new Registry holding the components of each registry, in the order the registries are given.
+// The given registries are not changed.
+func Merge(registries ...*Registry) *Registry {
+	merged := NewRegistry()
+	for _, r := range registries {
+		if r.err != nil && merged.err == nil {
+			merged.err = r.err
+		}
+		for _, name := range r.names {
+			merged.Add(name, r.components[strings.ToLower(name)])
+		}
+	}
+	return merged
+}
+
+// Provide returns the components of the registry, so that a Registry can be used as a Module.
+func (r *Registry) Provide() map[string]interface{} {
+	provided := make(map[string]interface{}, len(r.names))
+	for _, name := range r.names {
+		provided[name] = r.components[strings.ToLower(name)]
+	}
+	return provided
+}
+
+// registryReference is the reference used by an injector whose components come from a Registry.
+type registryReference struct{}
+
+// registerRegistry adds the components of r to the injector in the order they were added.
+func (i *injector) registerRegistry(r *Registry) ([]*component, error) {
+	if r.err != nil {
+		return nil, errorf(CodeDuplicate, "simplewire register failed - %v", r.err)
+	}
+	i.setReference(reflect.ValueOf(registryReference{}))
+	components := make([]*component, 0, len(r.names))
+	for _, name := range r.names {
+		c := &component{name: name, value: r.components[strings.ToLower(name)]}
+		i.provided[strings.ToLower(name)] = c
+		components = append(components, c)
+	}
+	return components, nil
+}
@@ -0,0 +1,51 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// usersRegistry declares the components of a users package.
+func usersRegistry(users *Users, accounts *AccountsS) *Registry {
+	return NewRegistry().Add("users", users).Add("accounts", accounts)
+}
+
+// storageRegistry declares the components of a storage package.
+func storageRegistry(db *MockDB) *Registry {
+	return NewRegistry().Add("db", db)
+}
+
+// TestRegistry tests that registries from separate packages can be merged and connected.
+func TestRegistry(t *testing.T) {
+	users, accounts, db := &Users{}, &AccountsS{}, &MockDB{}
+	injector, err := Connect("component", Merge(usersRegistry(users, accounts), storageRegistry(db)))
+	assert.NoError(t, err)
+	assert.True(t, users.initialized)
+	assert.Same(t, db, users.DB)
+	assert.Same(t, accounts, users.Accounts)
+	assert.Same(t, users, accounts.Users)
+
+	names := []string{}
+	for _, c := range injector.Graph().Components {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"users", "accounts", "db"}, names)
+
+	// a registry can also be a module of a reference struct
+	users, accounts = &Users{}, &AccountsS{}
+	_, err = Connect("component", struct {
+		UsersPackage   *Registry
+		StoragePackage *Registry
+	}{usersRegistry(users, accounts), storageRegistry(db)})
+	assert.NoError(t, err)
+	assert.Same(t, db, accounts.DB)
+
+	_, err = Connect("component", Merge(storageRegistry(db), NewRegistry().Add("DB", &MockDB{})))
+	assert.EqualError(t, err, "simplewire register failed - DB is registered more than once")
+
+	_, err = Connect("component", struct {
+		StoragePackage *Registry
+	}{Merge(storageRegistry(db), NewRegistry().Add("DB", &MockDB{}))})
+	assert.EqualError(t, err, "simplewire connect failed - DB is registered more than once")
+}
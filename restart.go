@@ -0,0 +1,139 @@
+package simplewire
+
+import (
+	"context"
+	"strings"
+)
+
+// Restart stops the named component and every component which depends on it, directly or transitively, then
+// rewires, initializes, and starts them again in dependency order.  Components are stopped in the reverse order and
+// brought back in the order they were initialized, so a component never runs while something it depends on is
+// stopped.  Consumers among them are drained first and subscribe again last, as in the Stop and Start phases.  The
+// component's current value is used, so Replace may be called first to swap in a new value.  If any step fails, the
+// components which were not brought back remain stopped.
+func (i *injector) Restart(ctx context.Context, name string) error {
+	if err := i.checkNotSubset("restart"); err != nil {
+		return err
+	}
+	if i.phase != PhaseInit && i.phase != PhaseStart {
+		return errorf(CodeWrongPhase, "simplewire restart failed - must follow the init or start phase, but the last phase was %s", i.phase)
+	}
+	affected := i.dependents(name)
+	if affected == nil {
+		return errorf(CodeNotFound, "simplewire restart failed - %s not found in reference struct", name)
+	}
+
+	restarted := []*component{}
+	draining := map[*component]bool{}
+	for _, c := range i.components {
+		if affected[strings.ToLower(c.name)] {
+			restarted = append(restarted, c)
+			draining[c] = true
+		}
+	}
+	// consumers are drained before anything they call is stopped, as in the Stop phase
+	if err := i.drainConsumers(ctx, "restart", draining); err != nil {
+		return err
+	}
+
+	for x := len(i.components) - 1; x >= 0; x-- {
+		c := i.components[x]
+		if !affected[strings.ToLower(c.name)] || !c.initialized {
+			continue
+		}
+		c.initialized = false
+		c.open = false
+		start := i.now()
+		called, err := stopComponent(ctx, c.value)
+		if called {
+			i.emitTimed("stop", PhaseStop, c.name, start, err)
+		}
+		if err != nil {
+			i.reportError(err, c.name, "")
+			return errorf(CodeStopFailed, "simplewire restart failed - could not stop %s: %w", c.name, err)
+		}
+	}
+
+	for _, c := range restarted {
+		if c.value != nil {
+			i.removeEdgesFrom(c.name)
+			err := i.injectSingle(c.name, c.value)
+			if err != nil {
+				return err
+			}
+			c.open = true
+		}
+		if skipsInit(c.value) {
+			continue
+		}
+		start := i.now()
+		called, err := initialize(ctx, c.value)
+		if called {
+			c.initDuration = i.since(start)
+			i.emitTimed("init", PhaseInit, c.name, start, err)
+		}
+		if err != nil {
+			i.reportError(err, c.name, "")
+			return &InitError{Component: c.name, Err: err}
+		}
+		c.initialized = true
+		if starter, ok := c.value.(Starter); ok && i.phase == PhaseStart {
+			start := i.now()
+			err := starter.Start(ctx)
+			i.emitTimed("start", PhaseStart, c.name, start, err)
+			if err != nil {
+				i.reportError(err, c.name, "")
+				return errorf(CodeStartFailed, "simplewire restart failed - could not start %s: %w", c.name, err)
+			}
+		}
+	}
+	if i.phase == PhaseStart {
+		// consumers subscribe again once everything they call has been started
+		return i.subscribe(ctx, "restart", restarted)
+	}
+	return nil
+}
+
+// dependents returns the lowercase names of the named component and every component which depends on it, directly
+// or transitively, according to the edges of the graph.  Nil is returned if there is no component named name.
+func (i *injector) dependents(name string) map[string]bool {
+	lname := strings.ToLower(name)
+	found := false
+	for _, c := range i.components {
+		if strings.ToLower(c.name) == lname {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+	dependedOnBy := map[string][]string{}
+	for _, e := range i.edges {
+		to := strings.ToLower(e.To)
+		dependedOnBy[to] = append(dependedOnBy[to], strings.ToLower(e.From))
+	}
+	affected := map[string]bool{}
+	pending := []string{lname}
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		if affected[next] {
+			continue
+		}
+		affected[next] = true
+		pending = append(pending, dependedOnBy[next]...)
+	}
+	return affected
+}
+
+// removeEdgesFrom removes the edges from the named component, before it is wired again.
+func (i *injector) removeEdgesFrom(name string) {
+	edges := i.edges[:0]
+	for _, e := range i.edges {
+		if e.From != name {
+			edges = append(edges, e)
+		}
+	}
+	i.edges = edges
+}
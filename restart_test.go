@@ -0,0 +1,58 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Dependent is a Recorder which depends on another Recorder.
+type Dependent struct {
+	Recorder
+	Base *Recorder `component:"base"`
+}
+
+// TestRestart tests that restarting a component also restarts its dependents, in dependency order.
+func TestRestart(t *testing.T) {
+	log := []string{}
+	components := struct {
+		Base     *Recorder
+		Consumer *Dependent
+		Other    *Recorder
+	}{
+		Base:     &Recorder{Name: "base", Log: &log},
+		Consumer: &Dependent{Recorder: Recorder{Name: "consumer", Log: &log}},
+		Other:    &Recorder{Name: "other", Log: &log},
+	}
+	ctx := context.Background()
+	injector, err := Register("component", components)
+	assert.NoError(t, err)
+	assert.NoError(t, injector.Wire())
+	assert.EqualError(t, injector.Restart(ctx, "base"), "simplewire restart failed - must follow the init or start phase, but the last phase was wire")
+	assert.NoError(t, injector.Init())
+	assert.NoError(t, injector.Start(ctx))
+
+	log = log[:0]
+	assert.NoError(t, injector.Restart(ctx, "base"))
+	assert.Equal(t, []string{
+		"stop consumer", "stop base",
+		"init base", "start base",
+		"init consumer", "start consumer",
+	}, log)
+
+	// a replaced value is not stopped by Restart, since Replace does not call lifecycle methods
+	log = log[:0]
+	replacement := &Recorder{Name: "replacement", Log: &log}
+	assert.NoError(t, injector.Replace("base", replacement))
+	assert.NoError(t, injector.Restart(ctx, "base"))
+	assert.Equal(t, []string{
+		"stop consumer",
+		"init replacement", "start replacement",
+		"init consumer", "start consumer",
+	}, log)
+	assert.Same(t, replacement, components.Consumer.Base, "the dependent should have been rewired")
+	assert.Len(t, injector.Graph().Edges, 1, "the edges of the dependent should not be duplicated")
+
+	assert.EqualError(t, injector.Restart(ctx, "missing"), "simplewire restart failed - missing not found in reference struct")
+}
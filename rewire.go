@@ -0,0 +1,55 @@
+package simplewire
+
+import "strings"
+
+// Replace swaps the component with the given name for another value.  Dependencies which were already injected
+// are not changed until Rewire is called, and no lifecycle methods are called on either value.
+func (i *injector) Replace(name string, value interface{}) error {
+	if err := i.checkNotSubset("replace"); err != nil {
+		return err
+	}
+	lname := strings.ToLower(name)
+	for _, c := range i.components {
+		if strings.ToLower(c.name) != lname {
+			continue
+		}
+		c.value = value
+		c.initialized = false
+		if _, ok := i.provided[lname]; !ok {
+			i.replaced[lname] = c
+		}
+		return nil
+	}
+	return errorf(CodeNotFound, "simplewire replace failed - %s not found in reference struct", name)
+}
+
+// Rewire injects dependencies again using the current set of components.  With no arguments, every component
+// is rewired; otherwise only each dest is.  Unlike Inject, Init is not called.
+func (i *injector) Rewire(dest ...interface{}) error {
+	if len(dest) > 0 {
+		for _, d := range dest {
+			if d == nil {
+				continue
+			}
+			err := i.injectSingle("", d)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := i.checkNotSubset("rewire"); err != nil {
+		return err
+	}
+	i.edges = nil
+	for _, c := range i.components {
+		if c.value == nil {
+			continue
+		}
+		err := i.injectSingle(c.name, c.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
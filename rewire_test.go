@@ -0,0 +1,37 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRewire tests that replacing a component and rewiring updates the components and destinations that use it.
+func TestRewire(t *testing.T) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	t1 := struct {
+		DB Database `component:"db"`
+	}{}
+	assert.NoError(t, injector.Inject(&t1))
+
+	replacement := &MockDB{}
+	assert.NoError(t, injector.Replace("db", replacement))
+	assert.Same(t, components.DB, components.Users.DB, "nothing should change until Rewire is called")
+
+	assert.NoError(t, injector.Rewire())
+	assert.Same(t, replacement, components.Users.DB, "components.Users should have been rewired with the replacement")
+	assert.Same(t, replacement, components.Accounts.(*AccountsS).DB, "components.Accounts should have been rewired with the replacement")
+	assert.Same(t, components.DB, t1.DB, "t1 is not a component, so it is only rewired when given")
+	assert.Len(t, injector.Graph().Edges, 4, "the edges should be recorded once")
+
+	assert.NoError(t, injector.Rewire(&t1))
+	assert.Same(t, replacement, t1.DB, "t1 should have been rewired with the replacement")
+
+	assert.EqualError(t, injector.Replace("cache", replacement), "simplewire replace failed - cache not found in reference struct")
+}
@@ -2,49 +2,340 @@
 package simplewire
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 )
 
-// Initializable can be implemented to automatically run initialization logic before dependency injection.
+// Initializable can be implemented to automatically run initialization logic.  A destination given to Inject is
+// initialized before its dependencies are injected.  The components of the reference are initialized during the Init
+// phase, after every component has been wired.
 type Initializable interface {
 	Init() error
 }
 
+// ContextInitializable can be implemented instead of Initializable by components whose initialization should stop
+// when the context given to ConnectContext is cancelled.  Other ways of running the Init phase give it a context
+// which is never cancelled.
+type ContextInitializable interface {
+	InitContext(ctx context.Context) error
+}
+
+// ConditionalInit can be implemented alongside Initializable to skip initialization, for example when a component
+// has been disabled by configuration.  The component is still wired and can be injected into other components.
+type ConditionalInit interface {
+	ShouldInit() bool
+}
+
+// Module can be implemented by a component in the reference to contribute additional components to the set of
+// dependencies.  This allows a feature to be packaged as a single component that brings along its own parts.
+type Module interface {
+	// Provide returns the contributed components, keyed by the name they will be injected with.  Names are matched
+	// the same way as the field names of the reference and must not collide with them.  The components are placed
+	// after those of the reference, sorted by name, so they are initialized after the module and stopped before it.
+	Provide() map[string]interface{}
+}
+
+// PerConsumer can be implemented by a component to inject a different value into each component that depends on it,
+// such as a logger which is tagged with the name of the component using it.
+type PerConsumer interface {
+	// ProvideFor returns the value to inject into the named consumer.  For destinations given to Inject, the
+	// consumer is named by its type.
+	ProvideFor(consumer string) interface{}
+}
+
+// Injectable can be implemented as an alternative to struct tags, for code which cannot or should not use them.
+// When a destination implements Injectable, its struct tags are ignored.
+type Injectable interface {
+	// Dependencies maps the name of each field to inject to what its tag would otherwise hold, which is the name of
+	// the component followed by any options, such as "config,value".
+	Dependencies() map[string]string
+}
+
+var injectableType = reflect.TypeOf((*Injectable)(nil)).Elem()
+
 // Connect will create a set of dependencies which can be injected by using the returned Injector.
 // Each field in the reference that is eligible to be injected will also have its own dependencies injected.
-// The reference interface should be a struct or pointer to a struct.
-func Connect(tag string, reference interface{}) (Injector, error) {
-	injector := injector{tag, reflect.Indirect(reflect.ValueOf(reference))}
-	return injector, injector.Inject(getFields(reference)...)
+// The reference interface should be a struct or pointer to a struct, or a *Registry.
+// Components which implement Module contribute their provided components to the set of dependencies as well.
+// Connect runs the Register, Wire, and Init phases; use Register to run each phase individually instead.
+//
+// A field of the reference which holds a slice, array, or map is a single component: its elements are not wired,
+// initialized, started, or stopped.  Declare each element as a field of its own, or give the field to Inject to wire
+// the elements.
+//
+// The order of processing is stable across runs.  Components are wired and initialized in the order they are
+// declared in the reference, followed by the components provided by modules, sorted by name for each module.  The
+// tagged fields of each component are injected in the order of their names, so the first error reported does not
+// depend on how the fields are arranged.  A name matches the reference field with exactly the same name first, and
+// otherwise the single field with the same name ignoring case.
+//
+// With WithConnectOnce, connecting a reference pointer which has already been connected returns the existing injector
+// instead of initializing the components again, until that injector is stopped.
+func Connect(tag string, reference interface{}, opts ...Option) (Injector, error) {
+	return ConnectContext(context.Background(), tag, reference, opts...)
+}
+
+// ConnectContext works the same way as Connect, but stops when ctx is cancelled, which lets a supervisor give an
+// application a bounded amount of time to start.  Cancellation is checked before each component is wired or
+// initialized, and the context is passed to components which implement ContextInitializable so they can stop early.
+// If ctx is cancelled during the Init phase, the components which were initialized are stopped.  The error is a
+// *CanceledError describing how far the injector got.
+func ConnectContext(ctx context.Context, tag string, reference interface{}, opts ...Option) (Injector, error) {
+	o := newOptions(options{}, opts)
+	if key := connectKey(reference); key != nil && o.connectOnce {
+		return connectOnce(key, tag, func() (*injector, error) {
+			return connect(ctx, tag, reference, o)
+		})
+	}
+	return connect(ctx, tag, reference, o)
+}
+
+// connect runs the Register, Wire, and Init phases for a new injector.
+func connect(ctx context.Context, tag string, reference interface{}, o options) (*injector, error) {
+	start := o.clock.Now()
+	injector, err := register(tag, reference, o, nil)
+	if err != nil {
+		return injector, err
+	}
+	err = injector.wire(ctx)
+	if err != nil {
+		return injector, err
+	}
+	err = injector.init(ctx)
+	if err != nil {
+		return injector, err
+	}
+	if err := injector.checkBudget(injector.since(start)); err != nil {
+		injector.reportError(err, "", "")
+		return injector, injector.rollback(context.Background(), err)
+	}
+	return injector, nil
+}
+
+// Register will create a set of dependencies from the reference the same way as Connect, but only runs the Register
+// phase.  The remaining phases must be run in order by calling the methods of the returned Injector.
+func Register(tag string, reference interface{}, opts ...Option) (Injector, error) {
+	return register(tag, reference, newOptions(options{}, opts), nil)
+}
+
+// register runs the Register phase for a new injector, which is a child of parent when parent is not nil.
+func register(tag string, reference interface{}, o options, parent *injector) (*injector, error) {
+	injector := &injector{
+		tag:          tag,
+		options:      o,
+		original:     reference,
+		parent:       parent,
+		provided:     map[string]*component{},
+		replaced:     map[string]*component{},
+		scoped:       map[string]*component{},
+		scopeLock:    &sync.Mutex{},
+		violations:   &violationLog{},
+		deprecations: &deprecationLog{},
+		cache:        &fieldCache{fields: map[reflect.Type][]injectField{}},
+		phase:        PhaseRegister,
+	}
+	if len(o.wiringErrs) > 0 {
+		return injector, errorf(CodeInvalidWiring, "simplewire register failed - %v", o.wiringErrs[0])
+	}
+	var components []*component
+	if r, ok := reference.(*Registry); ok && r != nil {
+		var err error
+		components, err = injector.registerRegistry(r)
+		if err != nil {
+			return injector, err
+		}
+	} else {
+		refValue, err := dereference(reflect.ValueOf(reference))
+		if err != nil {
+			return injector, errorf(CodeInvalidReference, "simplewire register failed - reference %v", err)
+		} else if refValue.Kind() != reflect.Struct {
+			return injector, errorf(CodeInvalidReference, "simplewire register failed - reference must be a struct or pointer to a struct, not %s", refValue.Type())
+		}
+		injector.setReference(refValue)
+		components = getComponents(refValue)
+	}
+	var err error
+	provided, err := injector.registerModules(components)
+	if err != nil {
+		return injector, err
+	}
+	injector.components = append(components, provided...)
+	if o.buildInfo {
+		err = injector.registerBuildInfo()
+		if err != nil {
+			return injector, err
+		}
+	}
+	err = injector.registerLabels()
+	if err != nil {
+		return injector, err
+	}
+	if o.strictInterfaces {
+		err = injector.checkInterfaces()
+		if err != nil {
+			return injector, err
+		}
+	}
+	return injector, nil
 }
 
 type Injector interface {
 	// Inject will iterate through each dest to inject dependencies. If a dest implements simplewire.Initializable, the Init method will be called.
+	// Each element of a dest which is a slice, array, or map is injected as well.
 	Inject(dest ...interface{}) error
+	// InjectValue injects dependencies into the value held by v the same way as Inject, for callers which already
+	// have a reflect.Value.  The value must be a pointer or otherwise addressable for its fields to be set.
+	InjectValue(v reflect.Value) error
+	// InjectWith injects dependencies into dest the same way as Inject, except that the fields named in overrides
+	// are injected with the component they map to instead of the one named by their tag.  This allows two values of
+	// the same type to be wired differently, such as to different databases.
+	InjectWith(dest interface{}, overrides map[string]string) error
+	// Wire runs the Wire phase, injecting the dependencies of every component.
+	Wire() error
+	// Init runs the Init phase, calling Init on every component that implements Initializable.
+	Init() error
+	// Start runs the Start phase, calling Start on every component that implements Starter.
+	Start(ctx context.Context) error
+	// Stop runs the Stop phase, calling Stop on every component that implements Stopper in the reverse order.
+	// Components that do not implement Stopper but have a Shutdown method or implement io.Closer are shut down or
+	// closed instead.
+	Stop(ctx context.Context) error
+	// Preload builds the named components which are declared with a Factory, and then calls Preload on those which
+	// implement Preloader, or does so for every component when no names are given.  It waits for them to finish.
+	Preload(ctx context.Context, names ...string) error
+	// Restart stops the named component and every component which depends on it, then rewires, initializes, and
+	// starts them again in dependency order.
+	Restart(ctx context.Context, name string) error
+	// StopGroup pauses the components whose group label is group, calling Stop in the reverse order on each which
+	// implements Stopper, while the rest of the graph keeps running.
+	StopGroup(ctx context.Context, group string) error
+	// StartGroup resumes the components of a group paused by StopGroup, calling Start in order on each which
+	// implements Starter.
+	StartGroup(ctx context.Context, group string) error
+	// Graph describes the components and the dependencies which were injected between them during the Wire phase.
+	Graph() Graph
+	// WithLabel returns the names of the components which have the label key set to value, in the order they are
+	// initialized.  Components are labeled with a labels tag on the reference or with the WithLabels option.
+	WithLabel(key, value string) []string
+	// Leaks reports the components which need to be stopped or closed, and were wired but have not been.
+	Leaks() []Leak
+	// Deprecations reports each field a deprecated component has been injected into, in the order they were injected.
+	Deprecations() []DeprecationUse
+	// AccessViolations reports each call to a component which had not finished initializing, made through a proxy
+	// injected by WithAccessChecks.
+	AccessViolations() []AccessViolation
+	// Health calls CheckHealth on every component that implements HealthChecker.  The result is keyed by component
+	// name, and a nil error means the component is healthy.
+	Health(ctx context.Context) map[string]error
+	// Replace swaps the component with the given name for another value.  Dependencies which were already injected
+	// are not changed until Rewire is called, and no lifecycle methods are called on either value.
+	Replace(name string, component interface{}) error
+	// Rewire injects dependencies again using the current set of components.  With no arguments, every component
+	// is rewired; otherwise only each dest is.  Unlike Inject, Init is not called.
+	Rewire(dest ...interface{}) error
+	// Child runs the Register phase for a new injector whose components may depend on the components of this one.
+	// Components of the child take precedence over components of the parent with the same name.  The child uses
+	// the same tag and options as its parent, and opts are applied on top of them.
+	Child(reference interface{}, opts ...Option) (Injector, error)
+	// Export returns a Bridge which shares the named components of the injector with the injectors it is given to
+	// with WithBridge.  The injector keeps ownership of the components, so only it runs their lifecycle methods.
+	Export(names ...string) (*Bridge, error)
+	// Subset returns a view of the injector which only exposes the named components and the components they depend
+	// on, directly or transitively.  The view cannot run lifecycle phases or replace components.
+	Subset(names ...string) (Injector, error)
+	// Lookup returns the component with the given name, matched the same way as struct tags.
+	Lookup(name string) (interface{}, bool)
+	// Compile injects a new value of the struct type dest points to, and returns a Plan which sets the same values on
+	// other destinations of that type without finding the components again.
+	Compile(dest interface{}) (*Plan, error)
+	// Reference returns the reference the injector was created with, for reading the components.  A subset returns
+	// nil.
+	Reference() interface{}
+	// WarmUp parses the tags of the type of each value ahead of time, so that later calls to Inject do not have to.
+	// An error is returned for the first tag which cannot be parsed.
+	WarmUp(types ...interface{}) error
 }
 
 type injector struct {
 	tag       string
+	options   options
 	reference reflect.Value
+	// original is the reference as it was given, before it was dereferenced
+	original interface{}
+	// refFields holds the fields of the reference keyed by lowercase name, so finding a component does not scan them
+	refFields map[string][]referenceField
+	// parent is the injector this one is a child of, which is used to find components this one does not have
+	parent *injector
+	// cache holds the tagged fields of each destination type
+	cache *fieldCache
+	// allowed holds the lowercase names of the only components which may be found, when the injector is a subset
+	allowed map[string]bool
+	// provided holds the components contributed by modules, keyed by lowercase name
+	provided map[string]*component
+	// replaced holds the components of the reference which were swapped by Replace, keyed by lowercase name
+	replaced map[string]*component
+	// scoped holds the instances of Scoped components built by this injector, keyed by lowercase name
+	scoped map[string]*component
+	// scopeLock is held while a Scoped component is built, so concurrent injections build a single instance
+	scopeLock *sync.Mutex
+	// components holds every component, the fields of the reference first followed by the provided components
+	components []*component
+	// wiring is the component whose dependencies are being injected during the Wire phase, which the Transient
+	// instances built for it are initialized with, and wiredScoped holds the Scoped instances built for it, which are
+	// placed ahead of it
+	wiring      *component
+	wiredScoped []*component
+	// edges holds the dependencies injected between components during the Wire phase
+	edges []Edge
+	// phase is the last lifecycle phase which completed
+	phase Phase
+	// deprecations holds each place a deprecated component was injected
+	deprecations *deprecationLog
+	// consuming holds the Consume methods which are running, in the order they were started
+	consuming []*consuming
+	// violations holds each call to a component which had not finished initializing, with WithAccessChecks
+	violations *violationLog
+	// connectedAs is the reference pointer the injector was created for by Connect, which is forgotten when it stops
+	connectedAs interface{}
+}
+
+// component is a single named dependency held by an injector.
+type component struct {
+	name         string
+	value        interface{}
+	initDuration time.Duration
+	// initialized is true once the component has passed the Init phase, until it is stopped.  A component which
+	// ConditionalInit skipped is never initialized, so it is neither started nor stopped.
+	initialized bool
+	// open is true once the component has been wired, until it is stopped
+	open bool
+	// paused is true while the component's group is stopped by StopGroup
+	paused bool
+	labels map[string]string
+	// transients holds the Transient instances injected into the component during the Wire phase, in the order
+	// they are initialized just before it
+	transients []*component
 }
 
 // Inject will iterate through each dest to inject dependencies. If a dest implements simplewire.Initializable, the Init method will be called.
-func (i injector) Inject(dest ...interface{}) error {
+// Each element of a dest which is a slice, array, or map is injected as well.
+func (i *injector) Inject(dest ...interface{}) error {
 	for _, d := range dest {
 		if d == nil {
 			continue
 		}
-		if hasInit, ok := d.(Initializable); ok {
-			err := hasInit.Init()
-			if err != nil {
-				return err
-			}
+		err := i.initDest(reflect.ValueOf(d))
+		if err != nil {
+			return err
 		}
-		err := i.injectSingle(d)
+		err = i.injectSingle("", d)
 		if err != nil {
 			return err
 		}
@@ -52,99 +343,528 @@ func (i injector) Inject(dest ...interface{}) error {
 	return nil
 }
 
-func (i injector) injectSingle(dest interface{}) (err error) {
+// InjectWith injects dependencies into dest the same way as Inject, except that the fields named in overrides are
+// injected with the component they map to instead of the one named by their tag.  The value may be followed by
+// options, such as "config,value", which replace the options of the tag.  Each field in overrides must be tagged.
+func (i *injector) InjectWith(dest interface{}, overrides map[string]string) error {
+	if dest == nil {
+		return nil
+	}
+	err := i.initDest(reflect.ValueOf(dest))
+	if err != nil {
+		return err
+	}
+	return i.injectValue("", reflect.ValueOf(dest), overrides, trace{})
+}
+
+// InjectValue injects dependencies into the value held by v the same way as Inject, for callers which already
+// have a reflect.Value.  The value must be a pointer or otherwise addressable for its fields to be set.
+func (i *injector) InjectValue(v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+	err := i.initDest(v)
+	if err != nil {
+		return err
+	}
+	return i.injectValue("", v, nil, trace{})
+}
+
+// initDest calls Init on a destination given to Inject, before its dependencies are injected.  A nil pointer is not
+// initialized, so that injecting it reports that it is nil.
+func (i *injector) initDest(v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if !v.Type().Implements(initializableType) && !v.Type().Implements(contextInitializableType) {
+		return nil
+	}
+	if skipsInit(v.Interface()) {
+		return nil
+	}
+	_, err := initialize(context.Background(), v.Interface())
+	if err != nil {
+		i.reportError(err, v.Type().String(), "")
+		return &InitError{Component: v.Type().String(), Err: err}
+	}
+	return nil
+}
+
+// skipsInit reports whether v implements ConditionalInit and should not be initialized.
+func skipsInit(v interface{}) bool {
+	conditional, ok := v.(ConditionalInit)
+	return ok && !conditional.ShouldInit()
+}
+
+var (
+	initializableType        = reflect.TypeOf((*Initializable)(nil)).Elem()
+	contextInitializableType = reflect.TypeOf((*ContextInitializable)(nil)).Elem()
+)
+
+// initialize calls InitContext if v implements ContextInitializable, or Init if v implements Initializable.  It
+// reports whether either method was called.  Callers check skipsInit first.
+func initialize(ctx context.Context, v interface{}) (bool, error) {
+	if hasInit, ok := v.(ContextInitializable); ok {
+		return true, hasInit.InitContext(ctx)
+	} else if hasInit, ok := v.(Initializable); ok {
+		return true, hasInit.Init()
+	}
+	return false, nil
+}
+
+// injectSingle injects the dependencies of dest.  When dest is a component, its name is given so the injected
+// dependencies can be recorded as edges of the graph.
+func (i *injector) injectSingle(name string, dest interface{}) error {
+	return i.injectValue(name, reflect.ValueOf(dest), nil, trace{})
+}
+
+// injectValue injects the dependencies of the value held by dest, the same way as injectSingle.  Overrides replace
+// the component names of the fields they name, as described by InjectWith.  The chain describes how dest was reached,
+// and is empty when the injection begins with dest.
+func (i *injector) injectValue(name string, dest reflect.Value, overrides map[string]string, chain trace) (err error) {
 	// in case of panic, preserve the names of the field that was being worked on
 	destStructName := ""
 	destFieldName := ""
+	// errors from the elements of a slice, array, or map were already reported when they were injected
+	nested := false
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("simplewire inject failed at %s:%s", destStructName, destFieldName)
+			err = errorf(CodeInternal, "simplewire inject failed at %s:%s", destStructName, destFieldName)
+		}
+		if _, ok := err.(*WiringError); err != nil && !ok && len(chain.steps) > 1 {
+			err = &WiringError{Chain: chain.steps, Err: err}
+		}
+		if err != nil && !nested {
+			component := name
+			if component == "" {
+				component = destStructName
+			}
+			if component == "" && dest.IsValid() {
+				component = dest.Type().String()
+			}
+			i.reportError(err, component, destFieldName)
 		}
 	}()
 
+	if chain.steps == nil && dest.IsValid() {
+		chain = rootTrace(name, dest.Type())
+	}
+	if err := i.checkDepth(chain); err != nil {
+		return err
+	}
+
 	// get value of the struct that is being injected
-	destValue := reflect.ValueOf(dest)
-	destValue = dereference(destValue)
+	destValue, err := dereference(dest)
+	if err != nil {
+		if name == "" {
+			name = "nil"
+			if dest.IsValid() {
+				name = dest.Type().String()
+			}
+		}
+		if err == errNilValue {
+			return errorf(CodeNil, "simplewire inject failed - destination %s is nil", name)
+		}
+		return errorf(CodeInvalidDestination, "simplewire inject failed - %s %v", name, err)
+	}
+
+	switch destValue.Kind() {
+	case reflect.Struct:
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if name == "" {
+			nested = true
+			return i.injectElements(destValue, chain)
+		}
+		return nil
+	default:
+		// components of other kinds, such as strings and funcs, have no dependencies, but a destination given to
+		// Inject is expected to
+		if name == "" {
+			return errorf(CodeInvalidDestination, "simplewire inject failed - unsupported destination kind %s for %s", destValue.Kind(), dest.Type())
+		}
+		return nil
+	}
 
-	destStructName = destValue.Type().Name()
+	destStructName = structName(destValue.Type())
+	if dest.Type().Implements(injectableType) {
+		deps := dest.Interface().(Injectable).Dependencies()
+		fieldNames := make([]string, 0, len(deps))
+		for fieldName := range deps {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		for _, fieldName := range fieldNames {
+			if _, ok := destValue.Type().FieldByName(fieldName); !ok {
+				return errorf(CodeInvalidWiring, "simplewire inject failed at %s:%s - field named by Dependencies does not exist", destStructName, fieldName)
+			}
+		}
+	}
+	// find the fields which have a tag with the inject key
+	fields := i.destFields(dest.Interface(), destValue.Type())
+	if overrides != nil {
+		var overridden string
+		fields, overridden, err = overrideFields(fields, overrides)
+		if err != nil {
+			destFieldName = overridden
+			return errorf(CodeInvalidTag, "simplewire inject failed at %s:%s - %v", destStructName, destFieldName, err)
+		}
+	}
 	if destValue.Kind() == reflect.Struct {
-		// for each field in the dest struct
-		for x := 0; x < destValue.NumField(); x++ {
-			destField := destValue.Type().Field(x)
-			destFieldName = destField.Name
-			// check if it has a tag with the inject key
-			refFieldName := destField.Tag.Get(i.tag)
-			if refFieldName == "" {
+		// for each tagged field in the dest struct
+		for _, field := range fields {
+			destFieldName = field.name
+			if field.err != nil {
+				return errorf(CodeInvalidTag, "simplewire inject failed at %s:%s - %v", destStructName, destFieldName, field.err)
+			}
+			refFieldName, opts := field.ref, field.opts
+			destFieldValue := destValue.Field(field.index)
+			if opts.labeled {
+				err := i.injectLabeled(name, destStructName, field, destFieldValue, chain)
+				if _, ok := err.(*WiringError); ok {
+					nested = true
+				}
+				if err != nil {
+					return err
+				}
 				continue
 			}
+			opt := asOptional(destFieldValue)
 			// if so, find the field in the reference
-			refField, err := i.getRefFieldByName(refFieldName)
-			if err != nil {
+			refName, refField, err := i.getRefFieldByName(refFieldName)
+			stubbed := false
+			if err == errFieldNotFound && opt == nil && i.options.stub != nil && destFieldValue.Kind() == reflect.Interface {
+				if stub := i.options.stub(destFieldValue.Type()); stub != nil {
+					refField, err, stubbed = stub, nil, true
+				}
+			}
+			if err == errFieldNotFound && opt != nil {
+				opt.setOptional(reflect.Value{})
+				continue
+			} else if err != nil {
 				if err == errFieldNotFound {
-					return fmt.Errorf("simplewire inject failed at %s:%s - %s not found in reference struct", destStructName, destFieldName, refFieldName)
+					return errorf(CodeNotFound, "simplewire inject failed at %s:%s - %s not found in reference struct", destStructName, destFieldName, refFieldName)
 				} else if err == errFieldNotExported {
-					return fmt.Errorf("simplewire inject failed at %s:%s - %s must be exported from reference struct", destStructName, destFieldName, refFieldName)
+					return errorf(CodeNotExported, "simplewire inject failed at %s:%s - %s must be exported from reference struct", destStructName, destFieldName, refFieldName)
+				} else if errors.Is(err, errFieldAmbiguous) {
+					return errorf(CodeAmbiguous, "simplewire inject failed at %s:%s - %v", destStructName, destFieldName, err)
 				}
 				panic(err) // no other error type is expected, but the panic is caught
 			}
+			deprecated, isDeprecated := refField.(Deprecated)
+			if f, ok := refField.(factory); ok {
+				refField, err = i.resolve(refName, f, chain.then(fmt.Sprintf("%s.%s (%s)", destStructName, destFieldName, refName)))
+				if _, ok := err.(*WiringError); ok {
+					// the error was reported where it happened
+					nested = true
+					return err
+				} else if err != nil {
+					return errorf(ErrorCode(err), "simplewire inject failed at %s:%s - %w", destStructName, destFieldName, err)
+				}
+			}
+			if perConsumer, ok := refField.(PerConsumer); ok {
+				consumer := name
+				if consumer == "" {
+					consumer = destStructName
+				}
+				refField = perConsumer.ProvideFor(consumer)
+			}
 
-			destFieldValue := destValue.FieldByIndex([]int{x})
 			refFieldValue := reflect.ValueOf(refField)
+			if !refFieldValue.IsValid() || (refFieldValue.Kind() == reflect.Ptr && refFieldValue.IsNil()) {
+				if opt != nil {
+					opt.setOptional(reflect.Value{})
+					continue
+				}
+				return errorf(CodeNil, "simplewire inject failed at %s:%s - %s is nil in reference struct", destStructName, destFieldName, refFieldName)
+			}
+			if opts.value && refFieldValue.Kind() == reflect.Ptr {
+				// a value is copied from what the pointer refers to
+				refFieldValue = refFieldValue.Elem()
+			}
+			// an Optional field is checked against the type it holds
+			destType := destFieldValue.Type()
+			if opt != nil {
+				destType = opt.optionalType()
+			}
 			// Check we will be able to set the destination field
 			if !unicode.IsUpper(rune(destFieldName[0])) {
-				return fmt.Errorf("simplewire inject failed at %s:%s - %s cannot be private", destStructName, destFieldName, destFieldName)
-			} else if destFieldValue.Kind() != reflect.Ptr && destFieldValue.Kind() != reflect.Interface {
-				return fmt.Errorf("simplewire inject failed at %s:%s - %s must be a pointer or interface", destStructName, destFieldName, destFieldName)
+				return errorf(CodePrivateField, "simplewire inject failed at %s:%s - %s cannot be private", destStructName, destFieldName, destFieldName)
+			} else if opts.value && destType.Kind() != reflect.Struct && !isScalar(destType.Kind()) {
+				return errorf(CodeInvalidField, "simplewire inject failed at %s:%s - %s must be a struct or scalar to be injected by value", destStructName, destFieldName, destFieldName)
+			} else if !opts.value && destType.Kind() != reflect.Ptr && destType.Kind() != reflect.Interface {
+				return errorf(CodeInvalidField, "simplewire inject failed at %s:%s - %s must be a pointer or interface", destStructName, destFieldName, destFieldName)
 			} else if !destFieldValue.CanSet() {
-				return fmt.Errorf("simplewire inject failed at %s:%s - %s cannot be changed", destStructName, destFieldName, destFieldName)
-			} else if !refFieldValue.Type().AssignableTo(destFieldValue.Type()) {
-				return fmt.Errorf("simplewire inject failed at %s:%s - %s is not assignable to %s", destStructName, destFieldName, refFieldValue.Type(), destFieldValue.Type())
+				return errorf(CodeInvalidField, "simplewire inject failed at %s:%s - %s cannot be changed", destStructName, destFieldName, destFieldName)
+			} else if !refFieldValue.Type().AssignableTo(destType) {
+				return errorf(CodeNotAssignable, "simplewire inject failed at %s:%s - %s is not assignable to %s", destStructName, destFieldName, refFieldValue.Type(), destType)
+			}
+			if i.options.accessChecks && name != "" && !stubbed && opt == nil && destType.Kind() == reflect.Interface {
+				refFieldValue = i.proxy(name, refName, destType, refFieldValue)
+			}
+			if opt != nil {
+				opt.setOptional(refFieldValue)
+			} else {
+				destFieldValue.Set(refFieldValue)
+			}
+			event := Event{Action: "inject", Component: name, Field: destFieldName, Dependency: refName}
+			if stubbed {
+				event.Action = "stub"
+				event.Dependency = refFieldName
+			}
+			if name != "" && !stubbed {
+				if err := i.checkEdgeLimit(); err != nil {
+					return errorf(ErrorCode(err), "simplewire inject failed at %s:%s - %v", destStructName, destFieldName, err)
+				}
+				i.edges = append(i.edges, Edge{From: name, Field: destFieldName, To: refName, Weak: opts.weak})
+				event.Phase = PhaseWire.String()
+			} else {
+				event.Component = destStructName
+			}
+			i.emit(event)
+			if isDeprecated {
+				i.recordDeprecation(DeprecationUse{
+					Component: refName,
+					Consumer:  event.Component,
+					Field:     destFieldName,
+					Message:   deprecated.Deprecated(),
+				})
 			}
-			destFieldValue.Set(refFieldValue)
 		}
 	}
 	return nil
 }
 
+// injectElements injects the dependencies of each element of a slice, array, or map given to Inject.  Elements of
+// slices and arrays are injected in order, and the values of a map are injected in the order of their sorted keys.
+// Elements which are nil interfaces are skipped.
+func (i *injector) injectElements(v reflect.Value, chain trace) error {
+	elems := []reflect.Value{}
+	steps := []string{}
+	if v.Kind() == reflect.Map {
+		keys := v.MapKeys()
+		sort.Slice(keys, func(a, b int) bool {
+			return fmt.Sprint(keys[a].Interface()) < fmt.Sprint(keys[b].Interface())
+		})
+		for _, k := range keys {
+			elems = append(elems, v.MapIndex(k))
+			steps = append(steps, fmt.Sprintf("[%v]", k.Interface()))
+		}
+	} else {
+		for x := 0; x < v.Len(); x++ {
+			elems = append(elems, v.Index(x))
+			steps = append(steps, fmt.Sprintf("[%d]", x))
+		}
+	}
+	for x, e := range elems {
+		if e.Kind() == reflect.Struct && e.CanAddr() {
+			// structs held directly in a slice can be changed through their address
+			e = e.Addr()
+		}
+		if e.Kind() == reflect.Interface && e.IsNil() {
+			continue
+		}
+		err := i.injectValue("", e, nil, chain.then(steps[x]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the component with the given name, matched the same way as struct tags.
+func (i *injector) Lookup(name string) (interface{}, bool) {
+	_, c, err := i.getRefFieldByName(name)
+	return c, err == nil
+}
+
 var (
 	errFieldNotFound    = errors.New("field not found")
 	errFieldNotExported = errors.New("field not exported")
+	errFieldAmbiguous   = errors.New("field name is ambiguous")
 )
 
-func (i injector) getRefFieldByName(name string) (interface{}, error) {
+// getRefFieldByName finds a component by name, returning the name it was declared with and its value.
+func (i *injector) getRefFieldByName(name string) (string, interface{}, error) {
+	if i.allowed != nil && !i.allowed[strings.ToLower(name)] {
+		return "", nil, errFieldNotFound
+	}
+	refName, refField, err := i.getOwnRefFieldByName(name)
+	if err == errFieldNotFound && len(i.options.bridges) > 0 {
+		refName, refField, err = i.getBridgedByName(name)
+	}
+	if err == errFieldNotFound && i.parent != nil {
+		return i.parent.getRefFieldByName(name)
+	}
+	return refName, refField, err
+}
+
+// getOwnRefFieldByName finds a component by name without looking at the parent injector.
+func (i *injector) getOwnRefFieldByName(name string) (string, interface{}, error) {
 	lname := strings.ToLower(name)
-	f := i.reference.FieldByNameFunc(func(n string) bool {
-		return strings.ToLower(n) == lname
-	})
+	if c, ok := i.replaced[lname]; ok {
+		return c.name, c.value, nil
+	}
+	refField, found, err := i.referenceField(name)
+	if err != nil {
+		return "", nil, err
+	}
+	var f reflect.Value
+	if found {
+		// an error means the field is promoted through a nil embedded pointer, so it is treated as missing
+		f, _ = i.reference.FieldByIndexErr(refField.index)
+	}
 	if !f.IsValid() {
-		return nil, errFieldNotFound
+		if c, ok := i.provided[lname]; ok {
+			return c.name, c.value, nil
+		}
+		return "", nil, errFieldNotFound
 	} else if !f.CanInterface() {
-		return nil, errFieldNotExported
+		return "", nil, errFieldNotExported
+	}
+	return refField.name, f.Interface(), nil
+}
+
+// referenceField is a field of the reference, which may be promoted from an embedded struct.
+type referenceField struct {
+	name  string
+	index []int
+}
+
+// setReference sets the reference of the injector and indexes its fields by lowercase name.
+func (i *injector) setReference(v reflect.Value) {
+	i.reference = v
+	i.refFields = map[string][]referenceField{}
+	for _, f := range reflect.VisibleFields(v.Type()) {
+		lname := strings.ToLower(f.Name)
+		i.refFields[lname] = append(i.refFields[lname], referenceField{name: f.Name, index: f.Index})
+	}
+	for _, fields := range i.refFields {
+		sort.Slice(fields, func(a, b int) bool {
+			return fields[a].name < fields[b].name
+		})
+	}
+}
+
+// referenceField finds the field of the reference which is named name.  A field with exactly the same name is
+// preferred, otherwise the name is matched without case.  If more than one field matches without case, the name is
+// ambiguous and an error is returned rather than picking one based on the order the fields are declared.  False is
+// returned when no field matches.
+func (i *injector) referenceField(name string) (referenceField, bool, error) {
+	matches := i.refFields[strings.ToLower(name)]
+	for _, f := range matches {
+		if f.name == name {
+			return f, true, nil
+		}
+	}
+	if len(matches) > 1 {
+		names := make([]string, len(matches))
+		for x, f := range matches {
+			names[x] = f.name
+		}
+		return referenceField{}, false, fmt.Errorf("%w: %s matches %s", errFieldAmbiguous, name, strings.Join(names, ", "))
+	} else if len(matches) == 0 {
+		return referenceField{}, false, nil
+	}
+	return matches[0], true, nil
+}
+
+// registerModules calls Provide on each component that implements Module and adds the returned components to the
+// injector.  Provided components which are modules themselves are registered as well.  The newly provided components
+// are returned in the order they were registered, which is sorted by name for each module.
+func (i *injector) registerModules(components []*component) ([]*component, error) {
+	provided := []*component{}
+	total := len(components)
+	if err := i.checkComponentLimit(total); err != nil {
+		return nil, err
 	}
-	return f.Interface(), nil
+	for len(components) > 0 {
+		m, ok := components[0].value.(Module)
+		components = components[1:]
+		if !ok {
+			continue
+		}
+		if r, ok := m.(*Registry); ok && r != nil && r.err != nil {
+			return nil, errorf(CodeDuplicate, "simplewire connect failed - %v", r.err)
+		}
+		contributed := m.Provide()
+		names := make([]string, 0, len(contributed))
+		for name := range contributed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, _, err := i.getOwnRefFieldByName(name); err != errFieldNotFound {
+				return nil, errorf(CodeDuplicate, "simplewire connect failed - %s is provided more than once", name)
+			}
+			c := &component{name: name, value: contributed[name]}
+			i.provided[strings.ToLower(name)] = c
+			provided = append(provided, c)
+			components = append(components, c)
+		}
+		total += len(names)
+		if err := i.checkComponentLimit(total); err != nil {
+			return nil, err
+		}
+	}
+	return provided, nil
 }
 
-// getFields will return a slice containing the values of all the exported fields of s
-func getFields(s interface{}) []interface{} {
-	v := reflect.ValueOf(s)
-	v = dereference(v)
-	fields := []interface{}{}
+// structName returns the name of the struct type t for errors, which is "anonymous struct" if it has none.
+func structName(t reflect.Type) string {
+	if t.Name() == "" {
+		return "anonymous struct"
+	}
+	return t.Name()
+}
+
+// getComponents will return a slice containing a component for each of the exported fields of the struct v
+func getComponents(v reflect.Value) []*component {
+	components := []*component{}
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		if field.CanInterface() {
-			fields = append(fields, field.Interface())
+			components = append(components, &component{name: v.Type().Field(i).Name, value: field.Interface()})
 		}
 	}
-	return fields
+	return components
+}
+
+// isScalar reports whether values of kind k are plain values such as strings, numbers, and durations.
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
 }
 
-func dereference(v reflect.Value) reflect.Value {
-	for {
-		kind := v.Type().Kind()
-		if kind == reflect.Interface || kind == reflect.Ptr {
-			v = v.Elem()
-		} else {
-			return v
+// maxDereferenceDepth is the most pointers and interfaces dereference will follow before giving up, which protects
+// against values that refer back to themselves.
+const maxDereferenceDepth = 64
+
+var (
+	errNilValue         = errors.New("is nil")
+	errTooManyIndirects = fmt.Errorf("has more than %d levels of pointers or interfaces", maxDereferenceDepth)
+)
+
+// dereference follows pointers and interfaces until it reaches a concrete value.  An error is returned if a nil
+// value is reached or if there are too many levels to follow.
+func dereference(v reflect.Value) (reflect.Value, error) {
+	for depth := 0; ; depth++ {
+		if !v.IsValid() {
+			return v, errNilValue
+		}
+		kind := v.Kind()
+		if kind != reflect.Interface && kind != reflect.Ptr {
+			return v, nil
+		} else if v.IsNil() {
+			return v, errNilValue
+		} else if depth == maxDereferenceDepth {
+			return v, errTooManyIndirects
 		}
+		v = v.Elem()
 	}
 }
@@ -2,7 +2,9 @@ package simplewire
 
 import (
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -95,6 +97,335 @@ func TestInject(t *testing.T) {
 	assert.Equal(t, testAccountID, accounts[0].AccountID)
 }
 
+// Config is a small immutable struct which can be injected by value.
+type Config struct {
+	Name  string
+	Limit int
+}
+
+// EarlyInit records whether its dependencies were injected when Init was called.
+type EarlyInit struct {
+	DB           Database `component:"db"`
+	initWithDeps bool
+}
+
+func (e *EarlyInit) Init() error {
+	e.initWithDeps = e.DB != nil
+	return nil
+}
+
+// TestInjectInitOrder tests that a destination given to Inject is initialized before its dependencies are injected.
+func TestInjectInitOrder(t *testing.T) {
+	injector, err := Connect("component", Components{&Users{}, &AccountsS{}, &MockDB{}})
+	assert.NoError(t, err)
+	dest := &EarlyInit{}
+	assert.NoError(t, injector.Inject(dest))
+	assert.False(t, dest.initWithDeps, "Init should be called before injection")
+	assert.NotNil(t, dest.DB)
+
+	var missing *EarlyInit
+	assert.EqualError(t, injector.Inject(missing), "simplewire inject failed - destination *simplewire.EarlyInit is nil")
+}
+
+// TestInjectValue tests that struct values are copied into fields tagged with the value option.
+func TestInjectValue(t *testing.T) {
+	components := struct {
+		Config    Config
+		ConfigPtr *Config
+	}{
+		Config:    Config{Name: "config", Limit: 1},
+		ConfigPtr: &Config{Name: "pointer", Limit: 2},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	t1 := struct {
+		Config    Config `component:"config,value"`
+		ConfigPtr Config `component:"configptr,value"`
+	}{}
+	assert.NoError(t, injector.Inject(&t1))
+	assert.Equal(t, components.Config, t1.Config)
+	assert.Equal(t, *components.ConfigPtr, t1.ConfigPtr)
+
+	t2 := struct {
+		Config Config `component:"config"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t2), "simplewire inject failed at anonymous struct:Config - Config must be a pointer or interface")
+
+	t3 := struct {
+		Config *Config `component:"config,value"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t3), "simplewire inject failed at anonymous struct:Config - Config must be a struct or scalar to be injected by value")
+
+	t4 := struct {
+		Config Config `component:"config,copy"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t4), `simplewire inject failed at anonymous struct:Config - unknown tag option "copy"`)
+}
+
+// TestDereference tests that destinations and references which cannot be dereferenced produce clear errors.
+func TestDereference(t *testing.T) {
+	injector, err := Connect("component", struct{}{})
+	assert.NoError(t, err)
+
+	var loop interface{}
+	loop = &loop
+	assert.EqualError(t, injector.Inject(loop), "simplewire inject failed - *interface {} has more than 64 levels of pointers or interfaces")
+
+	_, err = Connect("component", Components{})
+	assert.EqualError(t, err, "simplewire inject failed - destination Users is nil")
+
+	_, err = Connect("component", (*Components)(nil))
+	assert.EqualError(t, err, "simplewire register failed - reference is nil")
+	_, err = Connect("component", "components")
+	assert.EqualError(t, err, "simplewire register failed - reference must be a struct or pointer to a struct, not string")
+}
+
+// TestInjectNil tests that nil destinations and nil components produce clear errors.
+func TestInjectNil(t *testing.T) {
+	components := Components{
+		Users: &Users{},
+		DB:    &MockDB{},
+	}
+	injector, err := Register("component", components)
+	assert.NoError(t, err)
+	assert.EqualError(t, injector.Wire(), "simplewire inject failed at Users:Accounts - accounts is nil in reference struct")
+
+	assert.EqualError(t, injector.Inject((*AccountsS)(nil)), "simplewire inject failed - destination *simplewire.AccountsS is nil")
+	assert.NoError(t, injector.Inject(nil), "an untyped nil destination is skipped")
+
+	var users *Users
+	assert.NoError(t, injector.Replace("users", users))
+	assert.EqualError(t, injector.Inject(&AccountsS{}), "simplewire inject failed at AccountsS:Users - users is nil in reference struct")
+}
+
+// TestInjectScalar tests that scalar values are copied into fields tagged with the value option.
+func TestInjectScalar(t *testing.T) {
+	components := struct {
+		DSN     string
+		Port    int
+		Timeout time.Duration
+		Enabled *bool
+	}{
+		DSN:     "postgres://localhost",
+		Port:    5432,
+		Timeout: time.Second,
+		Enabled: new(bool),
+	}
+	*components.Enabled = true
+	injector, err := Connect("component", &components)
+	assert.NoError(t, err)
+
+	t1 := struct {
+		DSN     string        `component:"dsn,value"`
+		Port    int           `component:"port,value"`
+		Timeout time.Duration `component:"timeout,value"`
+		Enabled bool          `component:"enabled,value"`
+	}{}
+	assert.NoError(t, injector.Inject(&t1))
+	assert.Equal(t, "postgres://localhost", t1.DSN)
+	assert.Equal(t, 5432, t1.Port)
+	assert.Equal(t, time.Second, t1.Timeout)
+	assert.True(t, t1.Enabled)
+
+	t2 := struct {
+		Port int64 `component:"port,value"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t2), "simplewire inject failed at anonymous struct:Port - int is not assignable to int64")
+}
+
+// TestInjectKinds tests that slices, arrays, and maps of destinations have each element injected, and that other
+// kinds of destinations are rejected.
+func TestInjectKinds(t *testing.T) {
+	components := struct {
+		DB Database
+	}{
+		DB: &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	type Repository struct {
+		DB Database `component:"db"`
+	}
+	slice := []Repository{{}, {}}
+	assert.NoError(t, injector.Inject(slice))
+	assert.Same(t, components.DB, slice[0].DB)
+	assert.Same(t, components.DB, slice[1].DB)
+
+	array := [1]*Repository{{}}
+	assert.NoError(t, injector.Inject(&array))
+	assert.Same(t, components.DB, array[0].DB)
+
+	m := map[string]*Repository{"a": {}, "b": {}}
+	assert.NoError(t, injector.Inject(m))
+	assert.Same(t, components.DB, m["a"].DB)
+	assert.Same(t, components.DB, m["b"].DB)
+
+	var wrapped interface{} = &Repository{}
+	assert.NoError(t, injector.Inject(&wrapped))
+	assert.Same(t, components.DB, wrapped.(*Repository).DB)
+
+	assert.NoError(t, injector.Inject([]interface{}{nil}), "nil elements are skipped")
+	assert.EqualError(t, injector.Inject(map[string]Repository{"a": {}}), "simplewire inject failed at Repository:DB - DB cannot be changed - wiring chain: Inject(map[string]simplewire.Repository) -> [a]")
+	assert.EqualError(t, injector.Inject("repository"), "simplewire inject failed - unsupported destination kind string for string")
+	assert.EqualError(t, injector.Inject(func() {}), "simplewire inject failed - unsupported destination kind func for func()")
+}
+
+// TestComponentElements tests that a reference field holding a slice or map is a single component, whose elements
+// are neither wired nor initialized.
+func TestComponentElements(t *testing.T) {
+	type Repository struct {
+		DB Database `component:"db"`
+	}
+	log := []string{}
+	components := struct {
+		DB        Database
+		Repos     []*Repository
+		Recorders map[string]*Recorder
+	}{
+		DB:        &MockDB{},
+		Repos:     []*Repository{{}},
+		Recorders: map[string]*Recorder{"a": {Name: "a", Log: &log}},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Nil(t, components.Repos[0].DB)
+	assert.Empty(t, log)
+
+	// the elements can still be wired by giving the field to Inject
+	assert.NoError(t, injector.Inject(components.Repos))
+	assert.Same(t, components.DB, components.Repos[0].DB)
+}
+
+// TestInjectReflectValue tests injecting a value which is already held by a reflect.Value.
+func TestInjectReflectValue(t *testing.T) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	users := Users{}
+	assert.NoError(t, injector.InjectValue(reflect.ValueOf(&users).Elem()), "an addressable struct can be injected")
+	assert.Same(t, components.DB, users.DB)
+	assert.False(t, users.initialized, "Init has a pointer receiver, so it cannot be called on the struct")
+
+	assert.NoError(t, injector.InjectValue(reflect.ValueOf(&users)))
+	assert.True(t, users.initialized)
+
+	assert.EqualError(t, injector.InjectValue(reflect.ValueOf(Users{})), "simplewire inject failed at Users:Accounts - Accounts cannot be changed")
+	assert.NoError(t, injector.InjectValue(reflect.Value{}))
+}
+
+// AccountsModule is a module which brings its own Accounts implementation and Database.
+type AccountsModule struct{}
+
+func (m AccountsModule) Provide() map[string]interface{} {
+	return map[string]interface{}{
+		"Accounts": &AccountsS{},
+		"DB":       &MockDB{},
+	}
+}
+
+// TestInjectWith tests that two values of the same type can be wired to different components.
+func TestInjectWith(t *testing.T) {
+	components := struct {
+		Primary Database
+		Replica Database
+		Config  Config
+		Limits  Config
+	}{&MockDB{}, &MockDB{}, Config{Name: "app"}, Config{Limit: 10}}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	type Store struct {
+		DB     Database `component:"primary"`
+		Config Config   `component:"config,value"`
+	}
+	primary, replica := Store{}, Store{}
+	assert.NoError(t, injector.Inject(&primary))
+	assert.NoError(t, injector.InjectWith(&replica, map[string]string{"DB": "replica", "Config": "limits"}))
+	assert.Same(t, components.Primary, primary.DB)
+	assert.Same(t, components.Replica, replica.DB)
+	assert.Equal(t, components.Limits, replica.Config, "the options of the tag should be kept")
+
+	assert.EqualError(t, injector.InjectWith(&replica, map[string]string{"Cache": "replica"}), "simplewire inject failed at Store:Cache - overridden field is not tagged")
+}
+
+// TestModule tests that components provided by a module are injected and have their own dependencies injected.
+func TestModule(t *testing.T) {
+	type ModuleComponents struct {
+		Users   *Users
+		Modules AccountsModule
+	}
+	components := ModuleComponents{
+		Users: &Users{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.True(t, components.Users.initialized, "components.Users should have had the Init function called")
+	assert.IsType(t, &MockDB{}, components.Users.DB, "components.Users should have been wired with the module's DB")
+	accountsS, ok := components.Users.Accounts.(*AccountsS)
+	assert.True(t, ok, "components.Users should have been wired with the module's Accounts")
+	assert.Same(t, components.Users, accountsS.Users, "the module's Accounts should have been wired with a pointer to components.Users")
+	assert.Same(t, components.Users.DB, accountsS.DB, "the module's Accounts should have been wired with the module's DB")
+
+	t1 := struct {
+		DB Database `component:"db"`
+	}{}
+	assert.NoError(t, injector.Inject(&t1))
+	assert.Same(t, components.Users.DB, t1.DB, "t1 should have been wired with the module's DB")
+
+	// a module may not provide a name which is already in use
+	type ConflictingComponents struct {
+		DB      Database
+		Modules AccountsModule
+	}
+	_, err = Connect("component", ConflictingComponents{DB: &MockDB{}})
+	assert.EqualError(t, err, "simplewire connect failed - DB is provided more than once")
+}
+
+// TestOrder tests that fields are injected in the order of their names and that names are matched without depending
+// on the order of the reference's fields.
+func TestOrder(t *testing.T) {
+	db := &MockDB{}
+	injector, err := Connect("component", struct {
+		DB  Database
+		Db2 Database
+	}{DB: db, Db2: &MockDB{}})
+	assert.NoError(t, err)
+
+	// the first error reported is for the field with the lowest name, not the first one declared
+	t1 := struct {
+		Z Database `component:"missing"`
+		A Database `component:"db"`
+		B Database `component:"alsomissing"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t1), "simplewire inject failed at anonymous struct:B - alsomissing not found in reference struct")
+	assert.Same(t, db, t1.A, "fields before the failure should have been injected")
+
+	// an exact match is preferred over a match without case
+	injector, err = Connect("component", struct {
+		Db Database
+		DB Database
+	}{Db: &MockDB{}, DB: db})
+	assert.NoError(t, err)
+	t2 := struct {
+		DB Database `component:"DB"`
+	}{}
+	assert.NoError(t, injector.Inject(&t2))
+	assert.Same(t, db, t2.DB)
+
+	// without an exact match, a name matching more than one field is ambiguous
+	t3 := struct {
+		DB Database `component:"db"`
+	}{}
+	assert.EqualError(t, injector.Inject(&t3), "simplewire inject failed at anonymous struct:DB - field name is ambiguous: db matches DB, Db")
+}
+
 type User struct {
 	UserID   string
 	Username string
@@ -0,0 +1,40 @@
+//go:build go1.21
+
+// Package simplewireslog injects loggers from log/slog into components.  It is kept out of package simplewire so
+// that the module still supports versions of Go from before log/slog.
+package simplewireslog
+
+import "log/slog"
+
+// ComponentLogger is a component which injects a child of a root logger into every component that depends on it.
+// Each child is tagged with the name of the component it was injected into, so log records can be attributed
+// without each component having to do so itself.
+//
+//	type Components struct {
+//		Logger *simplewireslog.ComponentLogger
+//	}
+//
+//	type Users struct {
+//		Logger *slog.Logger `inject:"logger"`
+//	}
+type ComponentLogger struct {
+	// Root is the logger which every child is derived from.
+	Root *slog.Logger
+	// Key is the attribute key for the component name.  The default is "component".
+	Key string
+}
+
+// NewComponentLogger creates a ComponentLogger which derives children from root.
+func NewComponentLogger(root *slog.Logger) *ComponentLogger {
+	return &ComponentLogger{Root: root, Key: "component"}
+}
+
+// ProvideFor returns a child of the root logger which is tagged with the name of the consumer.  It implements
+// simplewire.PerConsumer.
+func (l *ComponentLogger) ProvideFor(consumer string) interface{} {
+	key := l.Key
+	if key == "" {
+		key = "component"
+	}
+	return l.Root.With(slog.String(key, consumer))
+}
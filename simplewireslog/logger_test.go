@@ -0,0 +1,47 @@
+//go:build go1.21
+
+package simplewireslog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/jswidler/simplewire"
+	"github.com/stretchr/testify/assert"
+)
+
+// Logging is a component which logs with a logger tagged with its own name.
+type Logging struct {
+	Logger *slog.Logger `component:"logger"`
+}
+
+// TestComponentLogger tests that each component is injected with a logger tagged with its name.
+func TestComponentLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	components := struct {
+		Logger *ComponentLogger
+		Users  *Logging
+	}{
+		Logger: NewComponentLogger(root),
+		Users:  &Logging{},
+	}
+	injector, err := simplewire.Connect("component", components)
+	assert.NoError(t, err)
+	components.Users.Logger.Info("hello")
+	assert.Equal(t, "level=INFO msg=hello component=Users\n", buf.String())
+
+	buf.Reset()
+	handler := Logging{}
+	assert.NoError(t, injector.Inject(&handler))
+	handler.Logger.Info("hello")
+	assert.Equal(t, "level=INFO msg=hello component=Logging\n", buf.String())
+}
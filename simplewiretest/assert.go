@@ -0,0 +1,96 @@
+package simplewiretest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jswidler/simplewire"
+)
+
+// AssertWired checks that the component named from was wired with each of the components named in to.  The failure
+// message lists every dependency the component was actually wired with.  It reports whether every edge was found.
+func AssertWired(t testing.TB, injector simplewire.Injector, from string, to ...string) bool {
+	t.Helper()
+	edges := []simplewire.Edge{}
+	for _, e := range injector.Graph().Edges {
+		if strings.EqualFold(e.From, from) {
+			edges = append(edges, e)
+		}
+	}
+	missing := []string{}
+	for _, name := range to {
+		found := false
+		for _, e := range edges {
+			if strings.EqualFold(e.To, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return true
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s is not wired with %s\n", from, strings.Join(missing, ", "))
+	if len(edges) == 0 {
+		fmt.Fprintf(b, "%s is not wired with any components", from)
+	} else {
+		fmt.Fprintf(b, "%s is wired with:", from)
+		for _, e := range edges {
+			fmt.Fprintf(b, "\n\t%s <- %s", e.Field, e.To)
+		}
+	}
+	t.Error(b.String())
+	return false
+}
+
+// AssertSame checks that actual, typically a field of a component, is the same instance as the component with the
+// given name.  It reports whether they are the same.
+func AssertSame(t testing.TB, injector simplewire.Injector, actual interface{}, name string) bool {
+	t.Helper()
+	expected, ok := injector.Lookup(name)
+	if !ok {
+		t.Errorf("%s is not a component", name)
+		return false
+	}
+	if !same(expected, actual) {
+		t.Errorf("not the same instance as %s\nexpected: %T(%p)\nactual:   %T(%p)", name, expected, expected, actual, actual)
+		return false
+	}
+	return true
+}
+
+// same reports whether a and b refer to the same instance, or are equal if they are plain values.
+func same(a, b interface{}) bool {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !av.IsValid() || !bv.IsValid() || av.Type() != bv.Type() {
+		return false
+	}
+	switch av.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return av.Pointer() == bv.Pointer()
+	}
+	return av.Type().Comparable() && a == b
+}
+
+// CheckLeaks fails the test if any component of the injector which needs to be stopped or closed is still open when
+// the test and its subtests complete, which catches tests that forget to stop the injector.
+func CheckLeaks(t testing.TB, injector simplewire.Injector) {
+	t.Helper()
+	t.Cleanup(func() {
+		leaks := injector.Leaks()
+		if len(leaks) == 0 {
+			return
+		}
+		names := make([]string, len(leaks))
+		for x, l := range leaks {
+			names[x] = fmt.Sprintf("%s (%s)", l.Component, l.Type)
+		}
+		t.Errorf("simplewiretest found components which were never stopped: %s", strings.Join(names, ", "))
+	})
+}
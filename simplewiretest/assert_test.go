@@ -0,0 +1,77 @@
+package simplewiretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jswidler/simplewire"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a testing.TB which records errors rather than failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, args[0].(string))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, format)
+}
+
+// TestAssertWired tests the wiring assertions pass for edges that exist and describe the edges that do not.
+func TestAssertWired(t *testing.T) {
+	store := MapStore{}
+	components := struct {
+		Service *Service
+		Store   Store
+		Other   *Service
+	}{
+		Service: &Service{},
+		Store:   store,
+		Other:   &Service{},
+	}
+	injector := Connect(t, "component", components)
+	assert.True(t, AssertWired(t, injector, "service", "store"))
+	assert.True(t, AssertSame(t, injector, components.Service.Store, "store"))
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertWired(r, injector, "service", "other"))
+	assert.Equal(t, []string{"service is not wired with other\nservice is wired with:\n\tStore <- Store"}, r.errors)
+
+	r = &recorder{TB: t}
+	assert.False(t, AssertSame(r, injector, components.Other, "service"))
+	assert.False(t, AssertSame(r, injector, components.Other, "cache"))
+	assert.Len(t, r.errors, 2)
+}
+
+// TestCheckLeaks tests that components which were never stopped are reported when the test completes.
+func TestCheckLeaks(t *testing.T) {
+	components := struct {
+		Service *Service
+		Store   Store
+	}{&Service{}, MapStore{}}
+	r := &recorder{}
+	t.Run("leaked", func(t *testing.T) {
+		r.TB = t
+		injector, err := simplewire.Connect("component", components)
+		assert.NoError(t, err)
+		CheckLeaks(r, injector)
+	})
+	assert.Equal(t, []string{"simplewiretest found components which were never stopped: %s"}, r.errors)
+
+	r = &recorder{}
+	t.Run("stopped", func(t *testing.T) {
+		r.TB = t
+		injector, err := simplewire.Connect("component", components)
+		assert.NoError(t, err)
+		CheckLeaks(r, injector)
+		assert.NoError(t, injector.Stop(context.Background()))
+	})
+	assert.Empty(t, r.errors)
+}
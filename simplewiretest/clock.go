@@ -0,0 +1,84 @@
+package simplewiretest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a simplewire.Clock whose time only moves when it is told to, for testing timing and retries without
+// real sleeps.  Sleep returns immediately after moving the clock forward, and the durations slept are recorded.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	sleeps  []time.Duration
+	waiters []fakeWaiter
+}
+
+// fakeWaiter is a channel returned by After, which receives once the clock reaches at.
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock returns a FakeClock which starts at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep moves the clock forward by d and records it.
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.move(d)
+	c.sleeps = append(c.sleeps, d)
+}
+
+// Advance moves the clock forward by d without recording it as a sleep, such as to make time pass while a component
+// initializes.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.move(d)
+}
+
+// After returns a channel which receives the time once the clock has been moved forward by d, with Sleep or Advance.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := fakeWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+	} else {
+		c.waiters = append(c.waiters, w)
+	}
+	return w.ch
+}
+
+// move moves the clock forward by d, and sends the time to each channel from After which it has reached.  The lock
+// must be held.
+func (c *FakeClock) move(d time.Duration) {
+	c.now = c.now.Add(d)
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiting = append(waiting, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	c.waiters = waiting
+}
+
+// Sleeps returns the duration of each call to Sleep, in order.
+func (c *FakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration{}, c.sleeps...)
+}
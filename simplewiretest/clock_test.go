@@ -0,0 +1,32 @@
+package simplewiretest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFakeClock tests that the clock only moves when told to.
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Sleep(time.Second)
+	clock.Advance(time.Minute)
+	clock.Sleep(2 * time.Second)
+	assert.Equal(t, start.Add(time.Minute+3*time.Second), clock.Now())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.Sleeps())
+
+	after := clock.After(time.Minute)
+	clock.Advance(59 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("After should not receive before the time has passed")
+	default:
+	}
+	clock.Sleep(time.Second)
+	assert.Equal(t, start.Add(2*time.Minute+3*time.Second), <-after)
+	assert.Equal(t, clock.Now(), <-clock.After(0))
+}
@@ -0,0 +1,74 @@
+package simplewiretest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jswidler/simplewire"
+)
+
+// StandIns returns a copy of the reference struct in which every nil component is replaced with a stand-in, so the
+// wiring of the components can be checked without building them.  Pointers to structs are replaced with a new zero
+// value, and interfaces with a stub registered with RegisterStub.  Components of other types, and interfaces without a
+// registered stub, are left as they are.
+func StandIns(reference interface{}) interface{} {
+	v := reflect.ValueOf(reference)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	for x := 0; x < copied.NumField(); x++ {
+		f := copied.Field(x)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Kind() == reflect.Ptr && f.IsNil() && f.Type().Elem().Kind() == reflect.Struct:
+			f.Set(reflect.New(f.Type().Elem()))
+		case f.Kind() == reflect.Interface && f.IsNil():
+			stubs.mu.RLock()
+			build, ok := stubs.build[f.Type()]
+			stubs.mu.RUnlock()
+			if ok {
+				f.Set(reflect.ValueOf(build(nil)))
+			}
+		}
+	}
+	return copied.Interface()
+}
+
+// AssertResolves checks that the tagged fields of the named component can be injected, without calling Init, and that
+// each of the named fields was given a value.  It reports whether they were.
+func AssertResolves(t testing.TB, injector simplewire.Injector, component string, fields ...string) bool {
+	t.Helper()
+	c, ok := injector.Lookup(component)
+	if !ok || c == nil {
+		t.Errorf("%s is not a component", component)
+		return false
+	}
+	err := injector.Rewire(c)
+	if err != nil {
+		t.Error(err.Error())
+		return false
+	}
+	v := reflect.ValueOf(c)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	empty := []string{}
+	for _, name := range fields {
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			empty = append(empty, name+" (no such field)")
+		} else if f.IsZero() && (f.Kind() == reflect.Ptr || f.Kind() == reflect.Interface) {
+			empty = append(empty, name)
+		}
+	}
+	if len(empty) > 0 {
+		t.Errorf("%s was not injected with %s", component, strings.Join(empty, ", "))
+		return false
+	}
+	return true
+}
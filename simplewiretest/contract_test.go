@@ -0,0 +1,45 @@
+package simplewiretest
+
+import (
+	"testing"
+
+	"github.com/jswidler/simplewire"
+	"github.com/stretchr/testify/assert"
+)
+
+// Outbox depends on a Notifier and a Mailer.
+type Outbox struct {
+	Notifier *Notifier `component:"notifier"`
+	Mailer   Mailer    `component:"mailer"`
+}
+
+// TestStandIns tests that the wiring of a reference can be checked with stand-ins for its components.
+func TestStandIns(t *testing.T) {
+	type Components struct {
+		Outbox   *Outbox
+		Notifier *Notifier
+		Mailer   Mailer
+		Store    Store
+	}
+	standIns := StandIns(Components{}).(Components)
+	assert.NotNil(t, standIns.Outbox)
+	assert.NotNil(t, standIns.Notifier)
+	assert.IsType(t, stubMailer{}, standIns.Mailer)
+	assert.Nil(t, standIns.Store, "interfaces without a registered stub should be left alone")
+
+	standIns.Store = MapStore{}
+	injector, err := simplewire.Register("component", standIns)
+	assert.NoError(t, err)
+	assert.True(t, AssertResolves(t, injector, "Outbox", "Mailer", "Notifier"))
+	assert.True(t, AssertResolves(t, injector, "Notifier", "Mailer", "Store"))
+
+	r := &recorder{TB: t}
+	injector, err = simplewire.Register("component", StandIns(Components{}))
+	assert.NoError(t, err)
+	assert.False(t, AssertResolves(r, injector, "Notifier", "Mailer", "Store"))
+	assert.False(t, AssertResolves(r, injector, "Missing"))
+	assert.Equal(t, []string{
+		"simplewire inject failed at Notifier:Store - store is nil in reference struct",
+		"%s is not a component",
+	}, r.errors)
+}
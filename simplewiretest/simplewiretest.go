@@ -0,0 +1,62 @@
+// Package simplewiretest provides helpers for using simplewire in tests.
+package simplewiretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jswidler/simplewire"
+)
+
+// Override replaces a component of the reference before it is wired, or changes how the components are wired.
+type Override struct {
+	Name      string
+	Component interface{}
+	// Option is given to the injector when it is set, and Name and Component are ignored.
+	Option simplewire.Option
+}
+
+// Replace returns an Override which replaces the component with the given name.
+func Replace(name string, component interface{}) Override {
+	return Override{Name: name, Component: component}
+}
+
+// Connect wires the components the same way as simplewire.Connect, after swapping in each override.  The injector
+// is stopped when the test and its subtests complete.  If the components cannot be wired, the test fails.
+func Connect(t testing.TB, tag string, components interface{}, overrides ...Override) simplewire.Injector {
+	t.Helper()
+	opts := []simplewire.Option{}
+	for _, o := range overrides {
+		if o.Option != nil {
+			opts = append(opts, o.Option)
+		}
+	}
+	injector, err := simplewire.Register(tag, components, opts...)
+	if err != nil {
+		t.Fatalf("simplewiretest could not register the components: %v", err)
+	}
+	for _, o := range overrides {
+		if o.Option != nil {
+			continue
+		}
+		err = injector.Replace(o.Name, o.Component)
+		if err != nil {
+			t.Fatalf("simplewiretest could not override %s: %v", o.Name, err)
+		}
+	}
+	err = injector.Wire()
+	if err != nil {
+		t.Fatalf("simplewiretest could not wire the components: %v", err)
+	}
+	err = injector.Init()
+	if err != nil {
+		t.Fatalf("simplewiretest could not initialize the components: %v", err)
+	}
+	t.Cleanup(func() {
+		err := injector.Stop(context.Background())
+		if err != nil {
+			t.Errorf("simplewiretest could not stop the components: %v", err)
+		}
+	})
+	return injector
+}
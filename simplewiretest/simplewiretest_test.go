@@ -0,0 +1,46 @@
+package simplewiretest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Store interface {
+	Get(key string) string
+}
+
+type MapStore map[string]string
+
+func (s MapStore) Get(key string) string {
+	return s[key]
+}
+
+type Service struct {
+	Store  Store `component:"store"`
+	closed bool
+}
+
+func (s *Service) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestConnect tests that overrides are wired in place of the reference's components and that the components are
+// stopped when the test completes.
+func TestConnect(t *testing.T) {
+	components := struct {
+		Service *Service
+		Store   Store
+	}{
+		Service: &Service{},
+		Store:   MapStore{"key": "real"},
+	}
+	t.Run("connect", func(t *testing.T) {
+		injector := Connect(t, "component", components, Replace("store", MapStore{"key": "fake"}))
+		assert.NotNil(t, injector)
+		assert.Equal(t, "fake", components.Service.Store.Get("key"))
+		assert.False(t, components.Service.closed)
+	})
+	assert.True(t, components.Service.closed, "the service should be closed when the subtest completes")
+}
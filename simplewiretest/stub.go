@@ -0,0 +1,44 @@
+package simplewiretest
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/jswidler/simplewire"
+)
+
+// stubs holds the function registered to build a stub for each interface type.
+var stubs = struct {
+	mu    sync.RWMutex
+	build map[reflect.Type]func(err error) interface{}
+}{build: map[reflect.Type]func(err error) interface{}{}}
+
+// RegisterStub registers build as the way to create a stub of the interface I.  The stub's methods should return
+// zero values, except for results of type error, which should be err.  Stubs are usually registered by the code
+// the simplewire stub command generates, rather than by hand.
+func RegisterStub[I any](build func(err error) I) {
+	t := reflect.TypeOf((*I)(nil)).Elem()
+	if t.Kind() != reflect.Interface {
+		panic("simplewiretest: stubs can only be registered for interfaces, not " + t.String())
+	}
+	stubs.mu.Lock()
+	defer stubs.mu.Unlock()
+	stubs.build[t] = func(err error) interface{} {
+		return build(err)
+	}
+}
+
+// AutoStub returns an Override which injects a registered stub into every interface field that names a component
+// which does not exist, so a test of a single component does not need a fake for every dependency.  The methods of
+// the stubs return err as their error results.
+func AutoStub(err error) Override {
+	return Override{Option: simplewire.WithStubs(func(t reflect.Type) interface{} {
+		stubs.mu.RLock()
+		build, ok := stubs.build[t]
+		stubs.mu.RUnlock()
+		if !ok {
+			return nil
+		}
+		return build(err)
+	})}
+}
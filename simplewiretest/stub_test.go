@@ -0,0 +1,52 @@
+package simplewiretest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Mailer is an interface which is stubbed in the tests.
+type Mailer interface {
+	Send(to string) (id string, err error)
+}
+
+// stubMailer is written the way the simplewire stub command generates it.
+type stubMailer struct {
+	err error
+}
+
+func (s stubMailer) Send(string) (r0 string, r1 error) {
+	r1 = s.err
+	return
+}
+
+func init() {
+	RegisterStub[Mailer](func(err error) Mailer { return stubMailer{err: err} })
+}
+
+// Notifier depends on a Mailer and a Store, neither of which is in the reference.
+type Notifier struct {
+	Mailer Mailer `component:"mailer"`
+	Store  Store  `component:"store"`
+}
+
+// TestAutoStub tests that registered stubs are injected for missing components.
+func TestAutoStub(t *testing.T) {
+	errStub := errors.New("stubbed")
+	components := struct {
+		Notifier *Notifier
+		Store    Store
+	}{&Notifier{}, MapStore{"key": "real"}}
+	Connect(t, "component", components, AutoStub(errStub))
+
+	id, err := components.Notifier.Mailer.Send("someone")
+	assert.Equal(t, "", id)
+	assert.Same(t, errStub, err)
+	assert.Equal(t, "real", components.Notifier.Store.Get("key"), "components which exist should not be stubbed")
+
+	assert.Panics(t, func() {
+		RegisterStub[MapStore](func(err error) MapStore { return nil })
+	}, "only interfaces can be stubbed")
+}
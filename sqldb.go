@@ -0,0 +1,91 @@
+package simplewire
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SQLDB is a component which owns a *sql.DB.  It is a module which provides the *sql.DB to the other components
+// under the name it was created with.  During the Init phase, the database is pinged until it responds, and during
+// the Stop phase the *sql.DB is closed.
+type SQLDB struct {
+	// PingAttempts is how many times the database is pinged during Init before giving up.  NewSQLDB sets it to 5, and
+	// the database is always pinged at least once.
+	PingAttempts int
+	// PingInterval is how long to wait between attempts to ping the database.  The default is one second.
+	PingInterval time.Duration
+	// PingJitter is the largest fraction of PingInterval which is added at random to each wait, so that instances
+	// which start together do not ping in lockstep.  The default is none.
+	PingJitter float64
+	// Clock is used to wait between attempts to ping the database.  The default is the SystemClock.
+	Clock Clock
+	// Random chooses the jitter added to each wait.  The default is the SystemRandom.
+	Random Random
+
+	name string
+	db   *sql.DB
+}
+
+// NewSQLDB opens a database with sql.Open.  The *sql.DB is provided to the other components with the given name.
+func NewSQLDB(name, driverName, dataSourceName string) (*SQLDB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLDB{
+		PingAttempts: 5,
+		PingInterval: time.Second,
+		Clock:        SystemClock,
+		Random:       SystemRandom,
+		name:         name,
+		db:           db,
+	}, nil
+}
+
+// DB returns the database.
+func (s *SQLDB) DB() *sql.DB {
+	return s.db
+}
+
+// Provide makes the *sql.DB available to inject.  As an io.Closer, it is closed during the Stop phase.
+func (s *SQLDB) Provide() map[string]interface{} {
+	return map[string]interface{}{s.name: s.db}
+}
+
+// Init pings the database, retrying until it responds or the attempts run out, in which case the database is closed.
+func (s *SQLDB) Init() error {
+	attempts := s.PingAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = s.db.Ping()
+		if err == nil {
+			return nil
+		}
+		if attempt < attempts {
+			clock := s.Clock
+			if clock == nil {
+				clock = SystemClock
+			}
+			clock.Sleep(s.pingWait())
+		}
+	}
+	// the *sql.DB is provided after this component, so it would not be closed by the rollback of the Init phase
+	_ = s.db.Close()
+	return fmt.Errorf("could not ping %s after %d attempts: %w", s.name, attempts, err)
+}
+
+// pingWait returns how long to wait before pinging the database again, with the jitter added.
+func (s *SQLDB) pingWait() time.Duration {
+	if s.PingJitter <= 0 {
+		return s.PingInterval
+	}
+	random := s.Random
+	if random == nil {
+		random = SystemRandom
+	}
+	return s.PingInterval + time.Duration(float64(s.PingInterval)*s.PingJitter*random.Float64())
+}
@@ -0,0 +1,103 @@
+package simplewire
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver is a database driver whose connections fail to ping until enough attempts have been made.
+type fakeDriver struct {
+	failures int
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("simplewire-fake", testDriver)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{d}, nil
+}
+
+func (c fakeConn) Ping(ctx context.Context) error {
+	if c.driver.failures > 0 {
+		c.driver.failures--
+		return errors.New("not ready")
+	}
+	return nil
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+// TestSQLDB tests that the database is provided for injection, pinged until ready, and closed when stopped.
+func TestSQLDB(t *testing.T) {
+	db, err := NewSQLDB("maindb", "simplewire-fake", "")
+	assert.NoError(t, err)
+	db.PingInterval = 0
+	testDriver.failures = 2
+
+	type Repository struct {
+		DB *sql.DB `component:"maindb"`
+	}
+	components := struct {
+		Database   *SQLDB
+		Repository *Repository
+	}{
+		Database:   db,
+		Repository: &Repository{},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+	assert.Same(t, db.DB(), components.Repository.DB)
+	assert.Equal(t, 0, testDriver.failures, "the database should have been pinged until it was ready")
+
+	assert.NoError(t, injector.Stop(context.Background()))
+	assert.EqualError(t, db.DB().Ping(), "sql: database is closed")
+
+	db, err = NewSQLDB("maindb", "simplewire-fake", "")
+	assert.NoError(t, err)
+	db.PingAttempts = 2
+	db.PingInterval = 0
+	testDriver.failures = 2
+	_, err = Connect("component", struct{ Database *SQLDB }{db})
+	assert.EqualError(t, err, "simplewire init failed at Database - could not ping maindb after 2 attempts: not ready")
+	assert.EqualError(t, db.DB().Ping(), "sql: database is closed", "the database should be closed when it cannot be pinged")
+
+	// the database is pinged once even when no attempts are configured
+	db, err = NewSQLDB("maindb", "simplewire-fake", "")
+	assert.NoError(t, err)
+	db.PingAttempts = 0
+	testDriver.failures = 1
+	_, err = Connect("component", struct{ Database *SQLDB }{db})
+	assert.EqualError(t, err, "simplewire init failed at Database - could not ping maindb after 1 attempts: not ready")
+	assert.Equal(t, 0, testDriver.failures)
+
+	// an SQLDB built without NewSQLDB waits with the system clock
+	opened, err := sql.Open("simplewire-fake", "")
+	assert.NoError(t, err)
+	db = &SQLDB{PingAttempts: 2, name: "maindb", db: opened}
+	testDriver.failures = 1
+	_, err = Connect("component", struct{ Database *SQLDB }{db})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, testDriver.failures)
+}
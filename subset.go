@@ -0,0 +1,56 @@
+package simplewire
+
+import "strings"
+
+// Subset returns a view of the injector which only exposes the named components and the components they depend on,
+// directly or transitively, for handing to code which should have a restricted view of the container.  The
+// dependencies are found from the graph, so Subset should be called after the Wire phase.  The view can inject
+// dependencies and describe its components, but it cannot run lifecycle phases or replace components.
+func (i *injector) Subset(names ...string) (Injector, error) {
+	dependsOn := map[string][]string{}
+	for _, e := range i.edges {
+		from := strings.ToLower(e.From)
+		dependsOn[from] = append(dependsOn[from], strings.ToLower(e.To))
+	}
+	allowed := map[string]bool{}
+	pending := []string{}
+	for _, name := range names {
+		if _, _, err := i.getRefFieldByName(name); err != nil {
+			return nil, errorf(CodeNotFound, "simplewire subset failed - %s not found in reference struct", name)
+		}
+		pending = append(pending, strings.ToLower(name))
+	}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if allowed[name] {
+			continue
+		}
+		allowed[name] = true
+		pending = append(pending, dependsOn[name]...)
+	}
+
+	view := *i
+	view.allowed = allowed
+	view.components = []*component{}
+	for _, c := range i.components {
+		if allowed[strings.ToLower(c.name)] {
+			view.components = append(view.components, c)
+		}
+	}
+	view.edges = []Edge{}
+	for _, e := range i.edges {
+		if allowed[strings.ToLower(e.From)] {
+			view.edges = append(view.edges, e)
+		}
+	}
+	return &view, nil
+}
+
+// checkNotSubset returns an error if the injector is a view made by Subset, which may not perform action.
+func (i *injector) checkNotSubset(action string) error {
+	if i.allowed != nil {
+		return errorf(CodeNotPermitted, "simplewire %s failed - not permitted on a subset", action)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSubset tests that a subset only exposes the named components and their dependencies.
+func TestSubset(t *testing.T) {
+	components := struct {
+		DB       Database
+		Accounts *AccountsS
+		Users    *Users
+		Config   Config
+	}{
+		DB:       &MockDB{},
+		Accounts: &AccountsS{},
+		Users:    &Users{},
+		Config:   Config{Name: "secret"},
+	}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	subset, err := injector.Subset("users")
+	assert.NoError(t, err)
+	names := []string{}
+	for _, c := range subset.Graph().Components {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"DB", "Accounts", "Users"}, names, "users depends on accounts and db")
+
+	t1 := struct {
+		Users *Users `component:"users"`
+	}{}
+	assert.NoError(t, subset.Inject(&t1))
+	assert.Same(t, components.Users, t1.Users)
+
+	t2 := struct {
+		Config Config `component:"config,value"`
+	}{}
+	assert.EqualError(t, subset.Inject(&t2), "simplewire inject failed at anonymous struct:Config - config not found in reference struct")
+	assert.EqualError(t, subset.Stop(context.Background()), "simplewire stop failed - not permitted on a subset")
+	assert.EqualError(t, subset.Replace("db", &MockDB{}), "simplewire replace failed - not permitted on a subset")
+
+	_, err = injector.Subset("cache")
+	assert.EqualError(t, err, "simplewire subset failed - cache not found in reference struct")
+}
@@ -0,0 +1,113 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CheckSubstitutable returns an error if mock cannot be used where a component of the interface type I is expected,
+// such as in place of a real component in a test.  The error names the methods which are missing, or explains that
+// the methods are declared with pointer receivers when mock is not a pointer.
+//
+//	assert.NoError(t, simplewire.CheckSubstitutable[Database](MockDB{}))
+func CheckSubstitutable[I any](mock interface{}) error {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		return errorf(CodeInvalidField, "simplewire check failed - %s is not an interface", iface)
+	}
+	if problem := substitutionProblem(iface, mock); problem != "" {
+		return errorf(CodeNotAssignable, "simplewire check failed - %s", problem)
+	}
+	return nil
+}
+
+// WithStrictInterfaces makes the Register phase check that every component can be injected into each field of an
+// interface type which names it, before any component is wired.  Every mismatch is reported together, including
+// components which only implement the interface through pointer receivers but are held by value.
+func WithStrictInterfaces() Option {
+	return func(o *options) {
+		o.strictInterfaces = true
+	}
+}
+
+// substitutionProblem describes why v does not implement the interface type iface, or returns an empty string if it
+// does.
+func substitutionProblem(iface reflect.Type, v interface{}) string {
+	if v == nil {
+		return fmt.Sprintf("nil does not implement %s", iface)
+	}
+	t := reflect.TypeOf(v)
+	if t.Implements(iface) {
+		return ""
+	}
+	if t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(iface) {
+		return fmt.Sprintf("%s does not implement %s because its methods have pointer receivers; use *%s instead", t, iface, t)
+	}
+	missing := []string{}
+	for x := 0; x < iface.NumMethod(); x++ {
+		want := iface.Method(x)
+		got, ok := t.MethodByName(want.Name)
+		if !ok {
+			missing = append(missing, want.Name)
+		} else if !sameSignature(want.Type, got.Type) {
+			missing = append(missing, want.Name+" (wrong signature)")
+		}
+	}
+	return fmt.Sprintf("%s does not implement %s, missing %s", t, iface, strings.Join(missing, ", "))
+}
+
+// sameSignature reports whether the method type of a concrete type, whose first argument is the receiver, has the
+// same signature as the method type of an interface.
+func sameSignature(iface, method reflect.Type) bool {
+	if method.NumIn()-1 != iface.NumIn() || method.NumOut() != iface.NumOut() || method.IsVariadic() != iface.IsVariadic() {
+		return false
+	}
+	for x := 0; x < iface.NumIn(); x++ {
+		if method.In(x+1) != iface.In(x) {
+			return false
+		}
+	}
+	for x := 0; x < iface.NumOut(); x++ {
+		if method.Out(x) != iface.Out(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkInterfaces returns an error describing every field of an interface type which names a component that does not
+// implement the interface.
+func (i *injector) checkInterfaces() error {
+	problems := []string{}
+	for _, c := range i.components {
+		destValue, err := dereference(reflect.ValueOf(c.value))
+		if err != nil || destValue.Kind() != reflect.Struct {
+			continue
+		}
+		t := destValue.Type()
+		for _, field := range i.destFields(c.value, t) {
+			fieldType := t.Field(field.index).Type
+			if field.err != nil || field.opts.value || fieldType.Kind() != reflect.Interface {
+				continue
+			}
+			_, ref, err := i.getRefFieldByName(field.ref)
+			if err != nil || ref == nil {
+				// missing components are reported when the component is wired
+				continue
+			}
+			switch ref.(type) {
+			case factory, PerConsumer:
+				// the value injected is not the component itself
+				continue
+			}
+			if problem := substitutionProblem(fieldType, ref); problem != "" {
+				problems = append(problems, fmt.Sprintf("%s:%s - %s", c.name, field.name, problem))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return errorf(CodeNotAssignable, "simplewire register failed - %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
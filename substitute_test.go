@@ -0,0 +1,58 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Pinger is implemented by the fakes below in different ways.
+type Pinger interface {
+	Ping() error
+}
+
+// pointerPinger implements Pinger with a pointer receiver.
+type pointerPinger struct{}
+
+func (p *pointerPinger) Ping() error {
+	return nil
+}
+
+// silentPinger has a Ping method with the wrong signature.
+type silentPinger struct{}
+
+func (p silentPinger) Ping() {}
+
+// Monitor depends on a Pinger.
+type Monitor struct {
+	Pinger Pinger `component:"pinger"`
+}
+
+// TestCheckSubstitutable tests that the reason a value does not implement an interface is explained.
+func TestCheckSubstitutable(t *testing.T) {
+	assert.NoError(t, CheckSubstitutable[Pinger](&pointerPinger{}))
+	assert.NoError(t, CheckSubstitutable[Database](MockDB{}))
+	assert.EqualError(t, CheckSubstitutable[Pinger](pointerPinger{}), "simplewire check failed - simplewire.pointerPinger does not implement simplewire.Pinger because its methods have pointer receivers; use *simplewire.pointerPinger instead")
+	assert.EqualError(t, CheckSubstitutable[Pinger](silentPinger{}), "simplewire check failed - simplewire.silentPinger does not implement simplewire.Pinger, missing Ping (wrong signature)")
+	assert.EqualError(t, CheckSubstitutable[Database](&Users{}), "simplewire check failed - *simplewire.Users does not implement simplewire.Database, missing AccountByID, AccountsByUserID, UserByID, UserByUsername")
+	assert.EqualError(t, CheckSubstitutable[Pinger](nil), "simplewire check failed - nil does not implement simplewire.Pinger")
+	assert.EqualError(t, CheckSubstitutable[MockDB](MockDB{}), "simplewire check failed - simplewire.MockDB is not an interface")
+}
+
+// TestStrictInterfaces tests that every mismatch is reported before any component is wired.
+func TestStrictInterfaces(t *testing.T) {
+	components := struct {
+		Pinger interface{}
+		First  *Monitor
+		Second *Monitor
+	}{pointerPinger{}, &Monitor{}, &Monitor{}}
+	_, err := Register("component", components, WithStrictInterfaces())
+	assert.EqualError(t, err, "simplewire register failed - "+
+		"First:Pinger - simplewire.pointerPinger does not implement simplewire.Pinger because its methods have pointer receivers; use *simplewire.pointerPinger instead; "+
+		"Second:Pinger - simplewire.pointerPinger does not implement simplewire.Pinger because its methods have pointer receivers; use *simplewire.pointerPinger instead")
+	assert.Equal(t, CodeNotAssignable, ErrorCode(err))
+
+	components.Pinger = &pointerPinger{}
+	_, err = Connect("component", components, WithStrictInterfaces())
+	assert.NoError(t, err)
+}
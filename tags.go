@@ -0,0 +1,167 @@
+package simplewire
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tagOptions holds the options which may follow the name in a struct tag, such as `inject:"config,value"`.
+type tagOptions struct {
+	// value copies the component into the field rather than requiring the field to be a pointer or interface, which
+	// allows structs and scalars such as strings, numbers, and durations to be injected
+	value bool
+	// weak marks the dependency as intentionally part of a cycle, so it is ignored by the cycle check
+	weak bool
+	// labeled injects a slice of every component with the label the name gives as key=value, rather than one component
+	labeled bool
+}
+
+// parseTag splits a struct tag into the name of the component and its options.
+func parseTag(tag string) (string, tagOptions, error) {
+	parts := strings.Split(tag, ",")
+	opts := tagOptions{}
+	for _, opt := range parts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "value":
+			opts.value = true
+		case "weak":
+			opts.weak = true
+		case "labeled":
+			opts.labeled = true
+		default:
+			return "", opts, fmt.Errorf("unknown tag option %q", opt)
+		}
+	}
+	return strings.TrimSpace(parts[0]), opts, nil
+}
+
+// injectField is a field of a destination struct which has a tag, parsed once per type.
+type injectField struct {
+	index int
+	name  string
+	// ref is the name of the component to inject
+	ref  string
+	opts tagOptions
+	// err is set when the tag could not be parsed, and is reported when the field is injected
+	err error
+}
+
+// parseFields finds the fields of the struct type t which have a tag, as returned by tagOf.  The fields are sorted by
+// name, so the order they are injected and the first error reported do not change when they are rearranged.
+func parseFields(t reflect.Type, tagOf func(f reflect.StructField) string) []injectField {
+	fields := []injectField{}
+	for x := 0; x < t.NumField(); x++ {
+		f := t.Field(x)
+		tag := tagOf(f)
+		if tag == "" {
+			continue
+		}
+		ref, opts, err := parseTag(tag)
+		fields = append(fields, injectField{index: x, name: f.Name, ref: ref, opts: opts, err: err})
+	}
+	sort.Slice(fields, func(a, b int) bool {
+		return fields[a].name < fields[b].name
+	})
+	return fields
+}
+
+// overrideFields returns a copy of fields with the component names replaced by overrides, which is keyed by field
+// name.  When a field named in overrides is not one of the fields, its name is returned with an error.
+func overrideFields(fields []injectField, overrides map[string]string) ([]injectField, string, error) {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	overridden := append([]injectField{}, fields...)
+	for _, name := range names {
+		x := sort.Search(len(overridden), func(x int) bool {
+			return overridden[x].name >= name
+		})
+		if x == len(overridden) || overridden[x].name != name {
+			return nil, name, errors.New("overridden field is not tagged")
+		}
+		ref, opts, err := parseTag(overrides[name])
+		if err != nil {
+			return nil, name, err
+		}
+		overridden[x].ref = ref
+		if strings.Contains(overrides[name], ",") {
+			overridden[x].opts = opts
+		}
+	}
+	return overridden, "", nil
+}
+
+// fieldCache holds the tagged fields of each destination type an injector has seen.
+type fieldCache struct {
+	mu     sync.RWMutex
+	fields map[reflect.Type][]injectField
+}
+
+// tagOf returns the tag of the field f with the injector's key, or else with the first of the keys from WithTagKeys
+// which the field has.
+func (i *injector) tagOf(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup(i.tag); ok {
+		return tag
+	}
+	for _, key := range i.options.tagKeys {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
+// fieldsOf returns the fields of the struct type t which have the injector's tag, parsing them the first time t is
+// seen.
+func (i *injector) fieldsOf(t reflect.Type) []injectField {
+	i.cache.mu.RLock()
+	fields, ok := i.cache.fields[t]
+	i.cache.mu.RUnlock()
+	if ok {
+		return fields
+	}
+	fields = parseFields(t, i.wiredTagOf(t, i.tagOf))
+	i.cache.mu.Lock()
+	i.cache.fields[t] = fields
+	i.cache.mu.Unlock()
+	return fields
+}
+
+// destFields returns the fields to inject of dest, whose struct type is t, which are declared by its Dependencies
+// when it implements Injectable, or otherwise by its tags.
+func (i *injector) destFields(dest interface{}, t reflect.Type) []injectField {
+	injectable, ok := dest.(Injectable)
+	if !ok {
+		return i.fieldsOf(t)
+	}
+	deps := injectable.Dependencies()
+	return parseFields(t, i.wiredTagOf(t, func(f reflect.StructField) string {
+		return deps[f.Name]
+	}))
+}
+
+// WarmUp parses the tags of the type of each value ahead of time, so that later calls to Inject do not have to.
+// An error is returned for the first tag which cannot be parsed.
+func (i *injector) WarmUp(types ...interface{}) error {
+	for _, v := range types {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			continue
+		}
+		for _, f := range i.fieldsOf(t) {
+			if f.err != nil {
+				return errorf(CodeInvalidTag, "simplewire warm up failed at %s:%s - %v", t.Name(), f.name, f.err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWarmUp tests that warming up caches the tagged fields of a type and reports tags which cannot be parsed.
+func TestWarmUp(t *testing.T) {
+	wired, err := Connect("component", struct{ DB Database }{&MockDB{}})
+	assert.NoError(t, err)
+
+	type Repository struct {
+		Name string
+		DB   Database `component:"db"`
+	}
+	assert.NoError(t, wired.WarmUp(&Repository{}, "not a struct", nil))
+	cached := wired.(*injector).cache.fields[reflect.TypeOf(Repository{})]
+	assert.Equal(t, []injectField{{index: 1, name: "DB", ref: "db"}}, cached)
+
+	type Broken struct {
+		DB Database `component:"db,copy"`
+	}
+	assert.EqualError(t, wired.WarmUp(Broken{}), `simplewire warm up failed at Broken:DB - unknown tag option "copy"`)
+}
+
+// Tagless declares its dependencies with Injectable rather than struct tags.
+type Tagless struct {
+	Users  *Users
+	DB     Database `component:"ignored"`
+	Config Config
+}
+
+func (t *Tagless) Dependencies() map[string]string {
+	return map[string]string{
+		"Users":  "users",
+		"DB":     "db",
+		"Config": "config,value",
+	}
+}
+
+// Misnamed names a field in Dependencies which it does not have.
+type Misnamed struct{}
+
+func (m Misnamed) Dependencies() map[string]string {
+	return map[string]string{"DB": "db"}
+}
+
+// TestInjectable tests that a destination which implements Injectable is wired from its Dependencies instead of tags.
+func TestInjectable(t *testing.T) {
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+		Config   Config
+	}{&Users{}, &AccountsS{}, &MockDB{}, Config{Name: "app"}}
+	injector, err := Connect("component", components)
+	assert.NoError(t, err)
+
+	tagless := &Tagless{}
+	assert.NoError(t, injector.Inject(tagless))
+	assert.Same(t, components.Users, tagless.Users)
+	assert.Same(t, components.DB, tagless.DB)
+	assert.Equal(t, components.Config, tagless.Config)
+
+	assert.EqualError(t, injector.Inject(&Misnamed{}), "simplewire inject failed at Misnamed:DB - field named by Dependencies does not exist")
+}
+
+// TestTagKeys tests that fields without the injector's tag are checked for the other tag keys in order.
+func TestTagKeys(t *testing.T) {
+	components := struct {
+		DB     Database
+		Config Config
+	}{&MockDB{}, Config{Name: "app"}}
+	injector, err := Connect("component", components, WithTagKeys("wire", "autowire"))
+	assert.NoError(t, err)
+
+	dest := struct {
+		First  Database `component:"db" wire:"missing"`
+		Second Database `autowire:"missing" wire:"db"`
+		Third  Config   `autowire:"config,value"`
+		Fourth Database `inject:"db"`
+	}{}
+	assert.NoError(t, injector.Inject(&dest))
+	assert.Same(t, components.DB, dest.First)
+	assert.Same(t, components.DB, dest.Second)
+	assert.Equal(t, components.Config, dest.Third)
+	assert.Nil(t, dest.Fourth, "keys which were not configured should be ignored")
+}
+
+// BenchmarkInject measures injecting a destination whose type has already been seen.
+func BenchmarkInject(b *testing.B) {
+	components := Components{
+		Users:    &Users{},
+		Accounts: &AccountsS{},
+		DB:       &MockDB{},
+	}
+	injector, err := Connect("component", components)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dest := &AccountsS{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		if err := injector.Inject(dest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// largeReference builds a reference with n components, each of which depends on the few components declared after it.
+func largeReference(n int) interface{} {
+	fields := make([]reflect.StructField, n)
+	for x := 0; x < n; x++ {
+		deps := make([]reflect.StructField, 4)
+		for y := range deps {
+			deps[y] = reflect.StructField{
+				Name: fmt.Sprintf("Dep%d", y),
+				Type: reflect.TypeOf((*interface{})(nil)).Elem(),
+				Tag:  reflect.StructTag(fmt.Sprintf(`component:"node%03d"`, (x+y+1)%n)),
+			}
+		}
+		fields[x] = reflect.StructField{Name: fmt.Sprintf("Node%03d", x), Type: reflect.PointerTo(reflect.StructOf(deps))}
+	}
+	reference := reflect.New(reflect.StructOf(fields)).Elem()
+	for x := 0; x < n; x++ {
+		reference.Field(x).Set(reflect.New(reference.Field(x).Type().Elem()))
+	}
+	return reference.Interface()
+}
+
+func BenchmarkConnectLarge(b *testing.B) {
+	reference := largeReference(150)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		if _, err := Connect("component", reference); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// scanReferenceFieldName is how a component was found before the fields of the reference were indexed, kept to
+// compare with the index.  Every field of the reference is scanned for each name.
+func scanReferenceFieldName(t reflect.Type, name string) (string, error) {
+	if _, ok := t.FieldByName(name); ok {
+		return name, nil
+	}
+	matches := []string{}
+	for _, f := range reflect.VisibleFields(t) {
+		if strings.EqualFold(f.Name, name) {
+			matches = append(matches, f.Name)
+		}
+	}
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return "", fmt.Errorf("%w: %s matches %s", errFieldAmbiguous, name, strings.Join(matches, ", "))
+	} else if len(matches) == 0 {
+		return "", nil
+	}
+	return matches[0], nil
+}
+
+// BenchmarkReferenceLookup compares finding every component of a large reference with the index built by Connect to
+// scanning the fields of the reference for each name, the way it was done before.
+func BenchmarkReferenceLookup(b *testing.B) {
+	reference := largeReference(150)
+	wired, err := Connect("component", reference)
+	if err != nil {
+		b.Fatal(err)
+	}
+	i := wired.(*injector)
+	names := make([]string, 150)
+	for x := range names {
+		names[x] = fmt.Sprintf("node%03d", x)
+	}
+
+	b.Run("index", func(b *testing.B) {
+		b.ReportAllocs()
+		for x := 0; x < b.N; x++ {
+			for _, name := range names {
+				if _, _, err := i.getOwnRefFieldByName(name); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		b.ReportAllocs()
+		for x := 0; x < b.N; x++ {
+			for _, name := range names {
+				refName, err := scanReferenceFieldName(i.reference.Type(), name)
+				if err != nil || refName == "" {
+					b.Fatal(name, err)
+				}
+				_ = i.reference.FieldByName(refName).Interface()
+			}
+		}
+	})
+}
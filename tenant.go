@@ -0,0 +1,133 @@
+package simplewire
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Child runs the Register phase for a new injector whose components may depend on the components of this one.
+// Components of the child take precedence over components of the parent with the same name.  The child uses
+// the same tag and options as its parent, except for labels, build info, and bridges, and opts are applied on top of
+// them.  Components imported by the parent through a bridge are found through the parent.
+func (i *injector) Child(reference interface{}, opts ...Option) (Injector, error) {
+	o := i.options
+	o.labels = nil
+	o.buildInfo = false
+	o.bridges = nil
+	return register(i.tag, reference, newOptions(o, opts), i)
+}
+
+// TenantPool builds and caches a child injector for each key, such as a tenant ID.  Each tenant's reference may hold
+// its own components, which take precedence over the shared components of the parent with the same name.
+type TenantPool struct {
+	parent Injector
+	build  func(key string) (interface{}, error)
+
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+type tenant struct {
+	key      string
+	injector Injector
+	lastUsed time.Time
+}
+
+// NewTenantPool creates a pool of children of parent.  The first time a key is used, build is called to create the
+// reference for the key's injector.
+func NewTenantPool(parent Injector, build func(key string) (interface{}, error)) *TenantPool {
+	return &TenantPool{
+		parent:  parent,
+		build:   build,
+		tenants: map[string]*tenant{},
+	}
+}
+
+// Get returns the injector for key.  If the key has not been used before, its injector is built and run through the
+// Wire, Init, and Start phases.  Injectors are built one at a time.
+func (p *TenantPool) Get(ctx context.Context, key string) (Injector, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, ok := p.tenants[key]; ok {
+		t.lastUsed = clockOf(p.parent).Now()
+		return t.injector, nil
+	}
+	reference, err := p.build(key)
+	if err != nil {
+		return nil, err
+	}
+	injector, err := p.parent.Child(reference)
+	if err != nil {
+		return nil, err
+	}
+	err = injector.Wire()
+	if err != nil {
+		return nil, err
+	}
+	err = injector.Init()
+	if err != nil {
+		return nil, err
+	}
+	err = injector.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.tenants[key] = &tenant{key: key, injector: injector, lastUsed: clockOf(p.parent).Now()}
+	return injector, nil
+}
+
+// Evict stops the injector for key and removes it from the pool.  The next call to Get for key builds a new one.
+func (p *TenantPool) Evict(ctx context.Context, key string) error {
+	p.mu.Lock()
+	t, ok := p.tenants[key]
+	delete(p.tenants, key)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return t.injector.Stop(ctx)
+}
+
+// EvictIdle stops and removes every injector which has not been returned by Get within maxIdle, in the order of
+// their keys.  Every idle injector is stopped even if some fail, in which case the first error is returned.
+func (p *TenantPool) EvictIdle(ctx context.Context, maxIdle time.Duration) error {
+	p.mu.Lock()
+	idle := []*tenant{}
+	for key, t := range p.tenants {
+		if clockOf(p.parent).Now().Sub(t.lastUsed) > maxIdle {
+			idle = append(idle, t)
+			delete(p.tenants, key)
+		}
+	}
+	p.mu.Unlock()
+	return stopTenants(ctx, idle)
+}
+
+// Stop stops and removes every injector in the pool, in the order of their keys.
+func (p *TenantPool) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	all := make([]*tenant, 0, len(p.tenants))
+	for _, t := range p.tenants {
+		all = append(all, t)
+	}
+	p.tenants = map[string]*tenant{}
+	p.mu.Unlock()
+	return stopTenants(ctx, all)
+}
+
+// stopTenants stops the injectors of tenants in the order of their keys.
+func stopTenants(ctx context.Context, tenants []*tenant) error {
+	sort.Slice(tenants, func(a, b int) bool {
+		return tenants[a].key < tenants[b].key
+	})
+	var firstErr error
+	for _, t := range tenants {
+		err := t.injector.Stop(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
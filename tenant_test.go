@@ -0,0 +1,71 @@
+package simplewire
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TenantClient is a component built for each tenant which depends on a shared component.
+type TenantClient struct {
+	Tenant string
+	DB     Database `component:"db"`
+	closed bool
+}
+
+func (c *TenantClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestTenantPool tests that each tenant gets its own cached injector which can use and override shared components.
+func TestTenantPool(t *testing.T) {
+	shared := struct {
+		DB Database
+	}{
+		DB: &MockDB{},
+	}
+	parent, err := Connect("component", shared)
+	assert.NoError(t, err)
+
+	tenantDB := &MockDB{}
+	clients := map[string]*TenantClient{}
+	pool := NewTenantPool(parent, func(key string) (interface{}, error) {
+		clients[key] = &TenantClient{Tenant: key}
+		if key == "isolated" {
+			return &struct {
+				Client *TenantClient
+				DB     Database
+			}{clients[key], tenantDB}, nil
+		}
+		return &struct {
+			Client *TenantClient
+		}{clients[key]}, nil
+	})
+
+	ctx := context.Background()
+	acme, err := pool.Get(ctx, "acme")
+	assert.NoError(t, err)
+	again, err := pool.Get(ctx, "acme")
+	assert.NoError(t, err)
+	assert.Same(t, acme, again, "the injector should be cached")
+	assert.Same(t, shared.DB, clients["acme"].DB, "acme should use the shared DB")
+
+	_, err = pool.Get(ctx, "isolated")
+	assert.NoError(t, err)
+	assert.Same(t, tenantDB, clients["isolated"].DB, "isolated should use its own DB")
+
+	assert.NoError(t, pool.EvictIdle(ctx, time.Hour))
+	assert.False(t, clients["acme"].closed)
+	assert.NoError(t, pool.Evict(ctx, "acme"))
+	assert.True(t, clients["acme"].closed, "acme should be stopped when evicted")
+	assert.NoError(t, pool.EvictIdle(ctx, 0))
+	assert.True(t, clients["isolated"].closed, "isolated should be stopped when idle")
+
+	rebuilt, err := pool.Get(ctx, "acme")
+	assert.NoError(t, err)
+	assert.NotSame(t, acme, rebuilt, "an evicted tenant should be rebuilt")
+	assert.NoError(t, pool.Stop(ctx))
+}
@@ -0,0 +1,68 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WiringError is returned when a dependency cannot be injected into a value which was reached through other
+// injections, such as a transient component built for a field, or an element of a slice given to Inject.  It
+// describes the chain of injections which led to the value, the same way a build tool prints a chain of imports.
+type WiringError struct {
+	// Chain holds each step from where the injection began to the value which failed, such as "component Handler",
+	// "Inject([]*app.Handler)", "[2]", or "Handler.Request (request)".
+	Chain []string
+	Err   error
+}
+
+func (e *WiringError) Error() string {
+	return fmt.Sprintf("%v - wiring chain: %s", e.Err, strings.Join(e.Chain, " -> "))
+}
+
+func (e *WiringError) Unwrap() error {
+	return e.Err
+}
+
+// trace is the chain of injections which led to a value being injected.  It belongs to a single call to Inject or to
+// the wiring of a single component, so concurrent injections never see each other's chains.
+type trace struct {
+	steps []string
+	// building holds the lowercase names of the Scoped and Transient components being built along the chain
+	building []string
+	// scoping is true when a Scoped component is being built along the chain, which means the chain holds the lock on
+	// the scoped components of the injector
+	scoping bool
+}
+
+// rootTrace begins the chain for the component named name, or for a destination of type t given to Inject when name
+// is empty.
+func rootTrace(name string, t reflect.Type) trace {
+	if name != "" {
+		return trace{steps: []string{"component " + name}}
+	}
+	return trace{steps: []string{fmt.Sprintf("Inject(%v)", t)}}
+}
+
+// then returns a copy of the chain with step added to the end.
+func (t trace) then(step string) trace {
+	t.steps = append(append([]string{}, t.steps...), step)
+	return t
+}
+
+// build returns a copy of the chain which is building the component with the lowercase name lname.
+func (t trace) build(lname string, scoped bool) trace {
+	t.building = append(append([]string{}, t.building...), lname)
+	t.scoping = t.scoping || scoped
+	return t
+}
+
+// isBuilding reports whether the component with the lowercase name lname is being built along the chain.
+func (t trace) isBuilding(lname string) bool {
+	for _, b := range t.building {
+		if b == lname {
+			return true
+		}
+	}
+	return false
+}
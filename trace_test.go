@@ -0,0 +1,39 @@
+package simplewire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Outer is reached from the reference and depends on a transient component.
+type Outer struct {
+	Middle *Middle `component:"middle"`
+}
+
+// Middle is built for each consumer and depends on a component which is missing.
+type Middle struct {
+	Missing Database `component:"missing"`
+}
+
+// TestWiringChain tests that an error from a value reached through other injections describes how it was reached.
+func TestWiringChain(t *testing.T) {
+	reported := 0
+	_, err := Connect("component", struct {
+		Outer  *Outer
+		Middle *Factory[*Middle]
+	}{&Outer{}, NewTransient(func() (*Middle, error) { return &Middle{}, nil })}, WithErrorHook(func(err error, component, field string) {
+		reported++
+	}))
+	assert.EqualError(t, err, "simplewire inject failed at Middle:Missing - missing not found in reference struct - wiring chain: component Outer -> Outer.Middle (Middle)")
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, []string{"component Outer", "Outer.Middle (Middle)"}, wiringErr.Chain)
+	assert.Equal(t, 1, reported, "the error should be reported once")
+
+	injector, err := Connect("component", struct{ DB Database }{&MockDB{}})
+	assert.NoError(t, err)
+	err = injector.Inject([]*Users{{}, {}})
+	assert.EqualError(t, err, "simplewire inject failed at Users:Accounts - accounts not found in reference struct - wiring chain: Inject([]*simplewire.Users) -> [0]")
+}
@@ -0,0 +1,114 @@
+package simplewire
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Wiring declares the dependencies of a struct type in code, with the same effect as struct tags.  It is meant for
+// types which cannot be modified, such as types from another module.  Wirings are passed to Connect or Register with
+// WithWiring, and are merged with the struct tags of the type.  When a field has both, the Wiring is used.
+type Wiring struct {
+	t      reflect.Type
+	fields map[string]string
+	err    error
+}
+
+// FieldWiring is a field of a Wiring which has not yet been given a component to inject.
+type FieldWiring struct {
+	wiring *Wiring
+	name   string
+}
+
+// Wire starts a Wiring for the type of dest, which is a struct or a pointer to a struct.  The value of dest is not
+// used, so a nil pointer such as (*sql.DB)(nil) may be given.
+//
+//	simplewire.Wire(&Handler{}).Field("DB").From("db").Field("Config").From("config,value")
+func Wire(dest interface{}) *Wiring {
+	w := &Wiring{fields: map[string]string{}}
+	t := reflect.TypeOf(dest)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		w.err = fmt.Errorf("wiring must be for a struct or pointer to a struct, not %v", t)
+		return w
+	}
+	w.t = t
+	return w
+}
+
+// Field selects the field of the struct to inject.
+func (w *Wiring) Field(name string) *FieldWiring {
+	if w.err == nil {
+		if _, ok := w.t.FieldByName(name); !ok {
+			w.err = fmt.Errorf("wiring for %s names field %s which does not exist", w.t, name)
+		}
+	}
+	return &FieldWiring{wiring: w, name: name}
+}
+
+// From sets the component to inject into the field.  The name may be followed by the same options as a tag, such as
+// "config,value".
+func (f *FieldWiring) From(component string) *Wiring {
+	f.wiring.fields[f.name] = component
+	return f.wiring
+}
+
+// MapType declares the dependencies of the struct type T from fields, which maps the name of each field to what its
+// tag would otherwise hold.  It is a shorthand for a Wiring given to WithWiring.
+//
+//	simplewire.MapType[vendor.Handler](map[string]string{"Client": "httpclient"})
+func MapType[T any](fields map[string]string) Option {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := Wire((*T)(nil))
+	for _, name := range names {
+		w.Field(name).From(fields[name])
+	}
+	return WithWiring(w)
+}
+
+// WithWiring adds the dependencies declared by each Wiring to the injector.
+func WithWiring(wirings ...*Wiring) Option {
+	return func(o *options) {
+		merged := map[reflect.Type]map[string]string{}
+		for t, fields := range o.wirings {
+			merged[t] = fields
+		}
+		for _, w := range wirings {
+			if w.err != nil {
+				o.wiringErrs = append(append([]error{}, o.wiringErrs...), w.err)
+				continue
+			}
+			fields := map[string]string{}
+			for name, tag := range merged[w.t] {
+				fields[name] = tag
+			}
+			for name, tag := range w.fields {
+				fields[name] = tag
+			}
+			merged[w.t] = fields
+		}
+		o.wirings = merged
+	}
+}
+
+// wiredTagOf returns a function which finds the tag of a field of the struct type t, preferring a Wiring given with
+// WithWiring over tagOf.
+func (i *injector) wiredTagOf(t reflect.Type, tagOf func(f reflect.StructField) string) func(f reflect.StructField) string {
+	wired, ok := i.options.wirings[t]
+	if !ok {
+		return tagOf
+	}
+	return func(f reflect.StructField) string {
+		if tag, ok := wired[f.Name]; ok {
+			return tag
+		}
+		return tagOf(f)
+	}
+}
@@ -0,0 +1,65 @@
+package simplewire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Untagged stands in for a type from another module which cannot be given struct tags.
+type Untagged struct {
+	DB     Database
+	Config Config
+	Users  *Users `component:"users"`
+}
+
+// TestWiring tests that dependencies declared with a Wiring are merged with struct tags.
+func TestWiring(t *testing.T) {
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+		Config   Config
+		Untagged *Untagged
+	}{&Users{}, &AccountsS{}, &MockDB{}, Config{Name: "app"}, &Untagged{}}
+	injector, err := Connect("component", components, WithWiring(
+		Wire((*Untagged)(nil)).Field("DB").From("db").Field("Config").From("config,value"),
+	))
+	assert.NoError(t, err)
+	assert.Same(t, components.DB, components.Untagged.DB)
+	assert.Equal(t, components.Config, components.Untagged.Config)
+	assert.Same(t, components.Users, components.Untagged.Users, "struct tags should still be used")
+
+	dest := Untagged{}
+	assert.NoError(t, injector.Inject(&dest))
+	assert.Same(t, components.DB, dest.DB)
+
+	_, err = Connect("component", components, WithWiring(Wire(Untagged{}).Field("Database").From("db")))
+	assert.EqualError(t, err, "simplewire register failed - wiring for simplewire.Untagged names field Database which does not exist")
+	_, err = Connect("component", components, WithWiring(Wire("db")))
+	assert.EqualError(t, err, "simplewire register failed - wiring must be for a struct or pointer to a struct, not string")
+}
+
+// TestMapType tests that the fields of a type can be mapped to components without tags.
+func TestMapType(t *testing.T) {
+	components := struct {
+		Users    *Users
+		Accounts Accounts
+		DB       Database
+		Config   Config
+	}{&Users{}, &AccountsS{}, &MockDB{}, Config{Name: "app"}}
+	injector, err := Connect("component", components, MapType[Untagged](map[string]string{
+		"DB":     "db",
+		"Config": "config,value",
+	}))
+	assert.NoError(t, err)
+
+	dest := Untagged{}
+	assert.NoError(t, injector.Inject(&dest))
+	assert.Same(t, components.DB, dest.DB)
+	assert.Equal(t, components.Config, dest.Config)
+	assert.Same(t, components.Users, dest.Users, "struct tags should still be used")
+
+	_, err = Connect("component", components, MapType[Untagged](map[string]string{"Client": "db", "Cache": "db"}))
+	assert.EqualError(t, err, "simplewire register failed - wiring for simplewire.Untagged names field Cache which does not exist")
+}